@@ -0,0 +1,212 @@
+// Command golexa contains the tooling that helps you keep your Go code and your Alexa interaction model
+// in sync, and helps you manage your translations. Run "golexa help" to see the available commands.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/robsignorelli/golexa/model"
+	"github.com/robsignorelli/golexa/speech"
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v2"
+)
+
+const usage = `Usage:
+
+  golexa model export [-out dir] [-locales en-US,es-MX] [package]
+      Runs your skill's main package and writes the interaction model JSON for each locale.
+      The package defaults to the current directory.
+
+  golexa model generate [-package name] [-out file] [-locale en-US] model.json
+      Generates Go constants, slot structs, and a handler interface from an interaction model.
+      Designed to be used w/ "go generate", so the package defaults to $GOPACKAGE.
+
+  golexa messages missing [-locales es-MX,fr-FR] [-format yaml] [-out dir] messages_dir
+      Finds the messages that haven't been translated for each locale and writes them w/ their
+      English text so you can send them to your translators. Prints to stdout unless you give it
+      an output directory, which gets one "<locale>.missing.<format>" file per locale.
+`
+
+func main() {
+	if len(os.Args) < 3 {
+		exitUsage()
+	}
+
+	var err error
+	switch os.Args[1] + " " + os.Args[2] {
+	case "model export":
+		err = modelExport(os.Args[3:])
+	case "model generate":
+		err = modelGenerate(os.Args[3:])
+	case "messages missing":
+		err = messagesMissing(os.Args[3:])
+	default:
+		exitUsage()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "golexa: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func exitUsage() {
+	fmt.Fprint(os.Stderr, usage)
+	os.Exit(2)
+}
+
+// modelExport runs the user's skill w/ GOLEXA_MODEL_EXPORT set, which tells `golexa.Start()` to
+// write the model files rather than listen for requests.
+func modelExport(args []string) error {
+	flags := flag.NewFlagSet("model export", flag.ExitOnError)
+	out := flags.String("out", "skill-package/interactionModels/custom", "The directory to write the model files to.")
+	locales := flags.String("locales", "en-US", "Comma separated list of locales to export.")
+	_ = flags.Parse(args)
+
+	pkg := "."
+	if flags.NArg() > 0 {
+		pkg = flags.Arg(0)
+	}
+	dir, err := filepath.Abs(*out)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("go", "run", pkg)
+	cmd.Env = append(os.Environ(), "GOLEXA_MODEL_EXPORT="+dir, "GOLEXA_MODEL_LOCALES="+*locales)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("unable to export model from '%s': %v", pkg, err)
+	}
+	fmt.Printf("Exported %s model(s) to %s\n", *locales, dir)
+	return nil
+}
+
+// modelGenerate reads an interaction model JSON file and writes the Go code for it.
+func modelGenerate(args []string) error {
+	flags := flag.NewFlagSet("model generate", flag.ExitOnError)
+	packageName := flags.String("package", os.Getenv("GOPACKAGE"), "The name of the package for the generated code.")
+	out := flags.String("out", "interaction_model.go", "The file to write the generated code to.")
+	locale := flags.String("locale", "", "The locale of the model. Defaults to the file name (e.g. en-US.json).")
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		exitUsage()
+	}
+	if *packageName == "" {
+		*packageName = "main"
+	}
+	if *locale == "" {
+		*locale = strings.TrimSuffix(filepath.Base(flags.Arg(0)), filepath.Ext(flags.Arg(0)))
+	}
+	lang, err := language.Parse(*locale)
+	if err != nil {
+		return fmt.Errorf("invalid locale '%s' (use -locale to set it): %v", *locale, err)
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	m, err := model.Parse(file, lang)
+	if err != nil {
+		return err
+	}
+	source, err := model.Generate(m, *packageName)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(*out, source, 0644)
+}
+
+// messagesMissing writes the English text of every message that is missing a translation.
+func messagesMissing(args []string) error {
+	flags := flag.NewFlagSet("messages missing", flag.ExitOnError)
+	locales := flags.String("locales", "", "Comma separated list of locales to check. Defaults to all of the message files.")
+	format := flags.String("format", "yaml", "The format to write the missing messages in: yaml or json.")
+	out := flags.String("out", "", "The directory to write one file per locale to. Defaults to stdout.")
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		exitUsage()
+	}
+	marshal, ok := map[string]func(interface{}) ([]byte, error){
+		"yaml": yaml.Marshal,
+		"json": func(value interface{}) ([]byte, error) {
+			data, err := json.MarshalIndent(value, "", "  ")
+			return append(data, '\n'), err
+		},
+	}[*format]
+	if !ok {
+		return fmt.Errorf("unsupported format '%s'", *format)
+	}
+
+	messages, err := speech.LoadMessages(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	var tags []language.Tag
+	for _, locale := range strings.Split(*locales, ",") {
+		if locale = strings.TrimSpace(locale); locale == "" {
+			continue
+		}
+		tag, err := language.Parse(locale)
+		if err != nil {
+			return fmt.Errorf("invalid locale '%s': %v", locale, err)
+		}
+		tags = append(tags, tag)
+	}
+
+	// Give the translators the English text (w/ any variants) for each missing message.
+	missing := map[string]map[string]interface{}{}
+	for lang, ids := range messages.Missing(tags...) {
+		missing[lang.String()] = map[string]interface{}{}
+		for _, id := range ids {
+			missing[lang.String()][id] = englishText(messages, id)
+		}
+	}
+
+	if *out == "" {
+		data, err := marshal(missing)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	if err := os.MkdirAll(*out, 0755); err != nil {
+		return err
+	}
+	for locale, texts := range missing {
+		data, err := marshal(texts)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(*out, locale+".missing."+*format), data, 0644); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Wrote missing messages for %d locale(s) to %s\n", len(missing), *out)
+	return nil
+}
+
+func englishText(messages speech.Messages, id string) interface{} {
+	texts, ok := messages.Text(language.AmericanEnglish, id)
+	if !ok {
+		texts, _ = messages.Text(language.English, id)
+	}
+	if len(texts) == 1 {
+		return texts[0]
+	}
+	return texts
+}
@@ -3,10 +3,13 @@ module github.com/robsignorelli/golexa
 go 1.12
 
 require (
+	github.com/BurntSushi/toml v0.3.1
 	github.com/aws/aws-lambda-go v1.12.1
 	github.com/sirupsen/logrus v1.6.0
 	github.com/stretchr/testify v1.3.0
 	golang.org/x/lint v0.0.0-20200302205851-738671d3881b // indirect
+	golang.org/x/oauth2 v0.0.0-20200107190931-bf48bf16ab8d
 	golang.org/x/text v0.3.2
 	golang.org/x/tools v0.0.0-20200522201501-cb1345f3a375 // indirect
+	gopkg.in/yaml.v2 v2.2.8
 )
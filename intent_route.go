@@ -0,0 +1,131 @@
+package golexa
+
+import (
+	"context"
+	"strings"
+
+	"github.com/robsignorelli/golexa/model"
+	"github.com/robsignorelli/golexa/speech"
+)
+
+// The dialog states that Alexa reports when your intent uses a dialog model.
+const (
+	DialogStateStarted    = "STARTED"
+	DialogStateInProgress = "IN_PROGRESS"
+	DialogStateCompleted  = "COMPLETED"
+)
+
+// RoutePredicate decides whether or not an intent route should handle the incoming request. This is
+// similar to the "canHandle" function in Amazon's official SDKs.
+type RoutePredicate func(ctx context.Context, request Request) bool
+
+// IntentRoute is a single registered handler for an intent. A route w/o any conditions is the fallback
+// for its intent. Once you add conditions using helpers like WhenSlot() or When(), the route only handles
+// requests where ALL of its conditions are true. Routes for the same intent are checked in the order you
+// registered them, and the first match wins; if none match, the plain route handles the request.
+//
+//	skill.RouteIntent("PlayIntent", playPodcast).WhenSlot("media", "podcast")
+//	skill.RouteIntent("PlayIntent", playMusic).WhenSlot("media", "music", "song")
+//	skill.RouteIntent("PlayIntent", whatShouldIPlay)
+type IntentRoute struct {
+	name        string
+	handlerFunc HandlerFunc
+	predicates  []RoutePredicate
+	definition  model.Intent
+}
+
+// Name returns the name of the intent that this route handles.
+func (route *IntentRoute) Name() string {
+	return route.name
+}
+
+// When only lets this route handle requests where your custom predicate returns true.
+func (route *IntentRoute) When(predicate RoutePredicate) *IntentRoute {
+	route.predicates = append(route.predicates, predicate)
+	return route
+}
+
+// WhenSlot only lets this route handle requests where the slot's resolved value (or the id that it
+// resolved to) is one of the given values, ignoring case. For multi-value slots, any of the user's values
+// can match. If you don't supply any values, the route handles requests where the slot has any value at all.
+func (route *IntentRoute) WhenSlot(slotName string, values ...string) *IntentRoute {
+	return route.When(func(ctx context.Context, request Request) bool {
+		if request.Body.Intent == nil {
+			return false
+		}
+		for _, slotValue := range request.Body.Intent.Slots[slotName].Values() {
+			if len(values) == 0 && slotValue.Resolve() != "" {
+				return true
+			}
+			for _, value := range values {
+				if strings.EqualFold(value, slotValue.Resolve()) || strings.EqualFold(value, slotValue.ResolveID()) {
+					return true
+				}
+			}
+		}
+		return false
+	})
+}
+
+// WhenDialogState only lets this route handle requests where the dialog is in one of the given
+// states such as DialogStateCompleted.
+func (route *IntentRoute) WhenDialogState(states ...string) *IntentRoute {
+	return route.When(func(ctx context.Context, request Request) bool {
+		for _, state := range states {
+			if request.Body.DialogState == state {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// WhenSession only lets this route handle requests where your function returns true for the current
+// session. This is handy for routing based on the session attributes you sent back in earlier responses.
+func (route *IntentRoute) WhenSession(predicate func(session Session) bool) *IntentRoute {
+	return route.When(func(ctx context.Context, request Request) bool {
+		return predicate(request.Session)
+	})
+}
+
+// Samples declares the utterances that trigger this intent in your interaction model (see `Skill.Model()`).
+// Refer to slots using "{slot_name}" and declare them w/ `Slot()`.
+//
+//	skill.RouteIntent("AddItem", addItem).
+//	    Samples(model.NewPhrases("add {item_name} to my list", "update my list").
+//	        WithTranslation(language.Spanish, "agrega {item_name} a mi lista")).
+//	    Slot(model.Slot{Name: "item_name", Type: "AMAZON.Food", Elicitation: &askForItem})
+func (route *IntentRoute) Samples(samples model.Phrases) *IntentRoute {
+	route.definition = route.definition.Merge(model.Intent{Samples: samples})
+	return route
+}
+
+// Slot declares one of the intent's slots in your interaction model (see `Skill.Model()`). Set the
+// slot's Elicitation prompt to make it required, ideally using the same speech template you use w/
+// `ElicitSlot()` so the prompt and its translations only live in one place.
+func (route *IntentRoute) Slot(slot model.Slot) *IntentRoute {
+	route.definition = route.definition.Merge(model.Intent{Slots: []model.Slot{slot}})
+	return route
+}
+
+// Confirm has Alexa confirm the whole intent w/ the user using the given prompt before sending it to your
+// skill. This only affects your interaction model (see `Skill.Model()`).
+func (route *IntentRoute) Confirm(prompt speech.Template) *IntentRoute {
+	route.definition = route.definition.Merge(model.Intent{Confirmation: &prompt})
+	return route
+}
+
+// conditional returns true if the route only handles some requests for its intent.
+func (route *IntentRoute) conditional() bool {
+	return len(route.predicates) > 0
+}
+
+// matches returns true if all of the route's conditions are satisfied by the request.
+func (route *IntentRoute) matches(ctx context.Context, request Request) bool {
+	for _, predicate := range route.predicates {
+		if !predicate(ctx, request) {
+			return false
+		}
+	}
+	return true
+}
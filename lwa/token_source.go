@@ -0,0 +1,230 @@
+package lwa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// DefaultTokenURL is the Login with Amazon endpoint used to exchange your skill's client
+// id/secret for an access token that can call the Alexa APIs.
+const DefaultTokenURL = "https://api.amazon.com/auth/o2/token"
+
+// The scopes for the out-of-session Alexa APIs that accept client credentials tokens.
+const (
+	ScopeProactiveEvents = "alexa::proactive_events"
+	ScopeSkillMessaging  = "alexa:skill_messaging"
+)
+
+// NewCredentials wraps your skill's client id/secret (the "Alexa Skill Messaging" credentials in the
+// "Permissions" section of the developer console) so you can obtain access tokens for the Alexa APIs
+// that you call outside of a skill session. Tokens are cached until shortly before they expire, so
+// you should create a single instance and share it throughout your program.
+func NewCredentials(clientID, clientSecret string, options ...CredentialsOption) *Credentials {
+	credentials := Credentials{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		tokenURL:     DefaultTokenURL,
+		expiryDelta:  time.Minute,
+		timeout:      10 * time.Second,
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+		sources:      map[string]*TokenSource{},
+	}
+	for _, opt := range options {
+		opt(&credentials)
+	}
+	return &credentials
+}
+
+// CredentialsOption tweaks the settings used to obtain tokens. Please use the built-in
+// helpers like WithTokenURL() and WithHTTPClient().
+type CredentialsOption func(*Credentials)
+
+// WithTokenURL changes the Login with Amazon endpoint used to obtain access tokens. This is
+// really only useful for pointing at a local mock server for testing.
+func WithTokenURL(tokenURL string) CredentialsOption {
+	return func(credentials *Credentials) {
+		credentials.tokenURL = tokenURL
+	}
+}
+
+// WithTimeout limits how long we wait on Login with Amazon for a new token. Callers that need a token
+// while we're fetching one wait for that fetch to finish, so this keeps a hung request from blocking
+// all of them even if your HTTP client or context has no timeout of its own. The default is 10 seconds.
+func WithTimeout(timeout time.Duration) CredentialsOption {
+	return func(credentials *Credentials) {
+		credentials.timeout = timeout
+	}
+}
+
+// WithHTTPClient overrides the HTTP client used to communicate with Login with Amazon.
+func WithHTTPClient(httpClient *http.Client) CredentialsOption {
+	return func(credentials *Credentials) {
+		credentials.httpClient = httpClient
+	}
+}
+
+// WithExpiryDelta determines how long before a token's actual expiration that we should treat it
+// as expired and fetch a new one. This keeps in-flight requests from using a token that expires
+// before Amazon receives them. The default is one minute.
+func WithExpiryDelta(delta time.Duration) CredentialsOption {
+	return func(credentials *Credentials) {
+		credentials.expiryDelta = delta
+	}
+}
+
+// Credentials is your skill's client id/secret along with a cache of the tokens obtained with them.
+type Credentials struct {
+	clientID     string
+	clientSecret string
+	tokenURL     string
+	expiryDelta  time.Duration
+	timeout      time.Duration
+	httpClient   *http.Client
+
+	mutex   sync.Mutex
+	sources map[string]*TokenSource
+}
+
+// TokenSource provides tokens for the given scope(s). Every call for the same set of scopes returns
+// the same source, so all of your API clients share one cached token per scope.
+func (credentials *Credentials) TokenSource(scopes ...string) *TokenSource {
+	scopes = append([]string{}, scopes...)
+	sort.Strings(scopes)
+	scope := strings.Join(scopes, " ")
+
+	credentials.mutex.Lock()
+	defer credentials.mutex.Unlock()
+
+	if source, ok := credentials.sources[scope]; ok {
+		return source
+	}
+	source := &TokenSource{credentials: credentials, scope: scope}
+	credentials.sources[scope] = source
+	return source
+}
+
+// TokenSource fetches and caches access tokens for a single set of scopes. It implements
+// `oauth2.TokenSource`, so you can use it anywhere the oauth2 package expects one. It is safe for
+// concurrent use; when the cached token expires, only one caller fetches a new one while the rest wait.
+type TokenSource struct {
+	credentials *Credentials
+	scope       string
+
+	mutex sync.Mutex
+	token *oauth2.Token
+}
+
+// Token returns the cached access token, fetching a new one from Login with Amazon if we don't
+// have one or it is about to expire.
+func (source *TokenSource) Token() (*oauth2.Token, error) {
+	return source.TokenContext(context.Background())
+}
+
+// TokenContext is the same as Token() except that the given context controls the HTTP request
+// to Login with Amazon if we need to fetch a new token.
+func (source *TokenSource) TokenContext(ctx context.Context) (*oauth2.Token, error) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	if source.valid() {
+		return source.token, nil
+	}
+	token, err := source.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	source.token = token
+	return token, nil
+}
+
+func (source *TokenSource) valid() bool {
+	if source.token == nil || source.token.AccessToken == "" {
+		return false
+	}
+	return time.Now().Add(source.credentials.expiryDelta).Before(source.token.Expiry)
+}
+
+// fetch uses the client credentials grant to obtain a brand new token from Login with Amazon.
+func (source *TokenSource) fetch(ctx context.Context) (*oauth2.Token, error) {
+	credentials := source.credentials
+	if credentials.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, credentials.timeout)
+		defer cancel()
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", credentials.clientID)
+	form.Set("client_secret", credentials.clientSecret)
+	form.Set("scope", source.scope)
+
+	request, err := http.NewRequest(http.MethodPost, credentials.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("lwa: access token: %v", err)
+	}
+	request = request.WithContext(ctx)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := credentials.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("lwa: access token: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		message, _ := ioutil.ReadAll(response.Body)
+		return nil, fmt.Errorf("lwa: access token: %s: %s", response.Status, strings.TrimSpace(string(message)))
+	}
+
+	body := struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}{}
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("lwa: access token: %v", err)
+	}
+	if body.AccessToken == "" {
+		return nil, fmt.Errorf("lwa: access token: response did not contain a token")
+	}
+	if body.ExpiresIn <= 0 {
+		// Otherwise we'd cache a token that's already expired and go back to Amazon on every call.
+		return nil, fmt.Errorf("lwa: access token: response did not contain a valid expiration")
+	}
+
+	return &oauth2.Token{
+		AccessToken: body.AccessToken,
+		TokenType:   body.TokenType,
+		Expiry:      time.Now().Add(time.Duration(body.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// AccessToken is a convenience that obtains a token from any `oauth2.TokenSource`, using the context
+// if the source supports it (such as our TokenSource), and returns just the raw access token string.
+func AccessToken(ctx context.Context, source oauth2.TokenSource) (string, error) {
+	var token *oauth2.Token
+	var err error
+
+	if contextSource, ok := source.(interface {
+		TokenContext(context.Context) (*oauth2.Token, error)
+	}); ok {
+		token, err = contextSource.TokenContext(ctx)
+	} else {
+		token, err = source.Token()
+	}
+	if err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
@@ -0,0 +1,163 @@
+package lwa_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/robsignorelli/golexa/lwa"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/oauth2"
+)
+
+func TestTokenSourceSuite(t *testing.T) {
+	suite.Run(t, new(TokenSourceSuite))
+}
+
+type TokenSourceSuite struct {
+	suite.Suite
+}
+
+// tokenServer fakes the Login with Amazon token endpoint. Each token it hands out includes the
+// requested scope and a sequence number so we can tell whether we got a cached or fresh token.
+func (suite TokenSourceSuite) tokenServer(expiresIn int, status int) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		_ = r.ParseForm()
+		_, _ = fmt.Fprintf(w, `{"access_token":"%s.%d","expires_in":%d,"token_type":"bearer"}`,
+			r.PostForm.Get("scope"), n, expiresIn)
+	}))
+	return server, &calls
+}
+
+func (suite TokenSourceSuite) TestCaching() {
+	server, calls := suite.tokenServer(3600, http.StatusOK)
+	defer server.Close()
+
+	credentials := lwa.NewCredentials("client.id", "client.secret", lwa.WithTokenURL(server.URL))
+	var source oauth2.TokenSource = credentials.TokenSource(lwa.ScopeProactiveEvents)
+
+	token, err := source.Token()
+	suite.Require().NoError(err,
+		"Should fetch a token successfully")
+	suite.Equal("alexa::proactive_events.1", token.AccessToken,
+		"Should request a token for the given scope")
+	suite.True(token.Expiry.After(time.Now().Add(59*time.Minute)),
+		"Should set the expiry based on 'expires_in'")
+
+	token, err = source.Token()
+	suite.Require().NoError(err,
+		"Should fetch a token successfully")
+	suite.Equal("alexa::proactive_events.1", token.AccessToken,
+		"Should use the cached token until it expires")
+	suite.Equal(int32(1), atomic.LoadInt32(calls),
+		"Should only hit the token endpoint once while the token is valid")
+}
+
+func (suite TokenSourceSuite) TestScopes() {
+	server, calls := suite.tokenServer(3600, http.StatusOK)
+	defer server.Close()
+
+	credentials := lwa.NewCredentials("client.id", "client.secret", lwa.WithTokenURL(server.URL))
+	suite.True(credentials.TokenSource("b", "a") == credentials.TokenSource("a", "b"),
+		"Should share the same source for the same set of scopes")
+
+	events, _ := lwa.AccessToken(context.TODO(), credentials.TokenSource(lwa.ScopeProactiveEvents))
+	messaging, _ := lwa.AccessToken(context.TODO(), credentials.TokenSource(lwa.ScopeSkillMessaging))
+	suite.Equal("alexa::proactive_events.1", events,
+		"Should fetch a token for the first scope")
+	suite.Equal("alexa:skill_messaging.2", messaging,
+		"Should fetch a separate token for the second scope")
+	suite.Equal(int32(2), atomic.LoadInt32(calls),
+		"Should cache tokens separately per scope")
+}
+
+func (suite TokenSourceSuite) TestExpiry() {
+	server, calls := suite.tokenServer(30, http.StatusOK)
+	defer server.Close()
+
+	credentials := lwa.NewCredentials("client.id", "client.secret",
+		lwa.WithTokenURL(server.URL),
+		lwa.WithExpiryDelta(time.Minute))
+	source := credentials.TokenSource(lwa.ScopeSkillMessaging)
+
+	first, _ := lwa.AccessToken(context.TODO(), source)
+	second, _ := lwa.AccessToken(context.TODO(), source)
+	suite.Equal("alexa:skill_messaging.1", first,
+		"Should fetch a new token when there's nothing cached")
+	suite.Equal("alexa:skill_messaging.2", second,
+		"Should fetch a new token when the cached one expires within the expiry delta")
+	suite.Equal(int32(2), atomic.LoadInt32(calls),
+		"Should hit the token endpoint for each refresh")
+}
+
+func (suite TokenSourceSuite) TestConcurrentRefresh() {
+	server, calls := suite.tokenServer(3600, http.StatusOK)
+	defer server.Close()
+
+	credentials := lwa.NewCredentials("client.id", "client.secret", lwa.WithTokenURL(server.URL))
+	source := credentials.TokenSource(lwa.ScopeSkillMessaging)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = source.Token()
+		}()
+	}
+	wg.Wait()
+	suite.Equal(int32(1), atomic.LoadInt32(calls),
+		"Concurrent callers should share a single refresh")
+}
+
+func (suite TokenSourceSuite) TestFailure() {
+	server, _ := suite.tokenServer(3600, http.StatusUnauthorized)
+	defer server.Close()
+
+	credentials := lwa.NewCredentials("client.id", "client.secret", lwa.WithTokenURL(server.URL))
+	_, err := credentials.TokenSource(lwa.ScopeSkillMessaging).Token()
+	suite.Error(err,
+		"Should fail when Login with Amazon rejects the credentials")
+}
+
+func (suite TokenSourceSuite) TestMissingExpiration() {
+	server, _ := suite.tokenServer(0, http.StatusOK)
+	defer server.Close()
+
+	credentials := lwa.NewCredentials("client.id", "client.secret", lwa.WithTokenURL(server.URL))
+	_, err := credentials.TokenSource(lwa.ScopeSkillMessaging).Token()
+	suite.Error(err,
+		"Should fail when the token doesn't have a valid expiration")
+}
+
+func (suite TokenSourceSuite) TestTimeout() {
+	hang := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-hang
+	}))
+	defer server.Close()
+	defer close(hang)
+
+	credentials := lwa.NewCredentials("client.id", "client.secret",
+		lwa.WithTokenURL(server.URL),
+		lwa.WithHTTPClient(&http.Client{}),
+		lwa.WithTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	_, err := credentials.TokenSource(lwa.ScopeSkillMessaging).Token()
+	suite.Error(err,
+		"Should fail when Login with Amazon doesn't respond in time")
+	suite.True(time.Since(start) < 5*time.Second,
+		"Should give up on the request even when the HTTP client has no timeout")
+}
@@ -0,0 +1,165 @@
+package messaging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/robsignorelli/golexa/lwa"
+	"golang.org/x/oauth2"
+)
+
+// The base URLs for the Alexa APIs in each region. Send your messages to the region
+// where your skill's users are located.
+const (
+	NorthAmerica = "https://api.amazonalexa.com"
+	Europe       = "https://api.eu.amazonalexa.com"
+	FarEast      = "https://api.fe.amazonalexa.com"
+)
+
+// DefaultTokenURL is the Login with Amazon endpoint used to exchange your skill's client
+// id/secret for an access token that can send skill messages.
+const DefaultTokenURL = lwa.DefaultTokenURL
+
+// NewClient creates a client for the Skill Messaging API that lets your background jobs send
+// messages to your skill on behalf of a user. Alexa delivers each message to your skill as a
+// "Messaging.MessageReceived" request, which you can handle using `Skill.OnMessage()`. The
+// client id and secret are the "Alexa Skill Messaging" credentials in the "Permissions" section
+// of your skill in the developer console.
+//
+// See: https://developer.amazon.com/en-US/docs/alexa/smapi/skill-messaging-api-reference.html
+func NewClient(clientID, clientSecret string, options ...ClientOption) *Client {
+	client := Client{
+		apiURL:       NorthAmerica,
+		tokenURL:     DefaultTokenURL,
+		expiresAfter: time.Hour,
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+	}
+	for _, opt := range options {
+		opt(&client)
+	}
+	if client.tokens == nil {
+		credentials := lwa.NewCredentials(clientID, clientSecret,
+			lwa.WithTokenURL(client.tokenURL),
+			lwa.WithHTTPClient(client.httpClient))
+		client.tokens = credentials.TokenSource(lwa.ScopeSkillMessaging)
+	}
+	return &client
+}
+
+// NewTokenSourceClient creates a client that gets its access tokens from the given source rather than
+// fetching its own, so you don't need to pass along a client id/secret. This is the same as calling
+// `NewClient()` w/ blank credentials and `WithTokenSource()`:
+//
+//	credentials := lwa.NewCredentials(clientID, clientSecret)
+//	client := messaging.NewTokenSourceClient(credentials.TokenSource(lwa.ScopeSkillMessaging))
+func NewTokenSourceClient(tokens oauth2.TokenSource, options ...ClientOption) *Client {
+	return NewClient("", "", append([]ClientOption{WithTokenSource(tokens)}, options...)...)
+}
+
+// ClientOption tweaks the settings of your skill messaging client. Please use the built-in
+// helpers like WithAPIURL() and WithExpiration().
+type ClientOption func(*Client)
+
+// WithAPIURL changes the base URL of the Alexa API that messages are sent to. Use one of the region
+// constants such as messaging.Europe or the address of a local mock server for testing.
+func WithAPIURL(apiURL string) ClientOption {
+	return func(client *Client) {
+		client.apiURL = strings.TrimSuffix(apiURL, "/")
+	}
+}
+
+// WithTokenURL changes the Login with Amazon endpoint used to obtain access tokens. This is
+// really only useful for pointing the client at a local mock server for testing.
+func WithTokenURL(tokenURL string) ClientOption {
+	return func(client *Client) {
+		client.tokenURL = tokenURL
+	}
+}
+
+// WithExpiration determines how long Alexa will hold onto a message it was unable to deliver
+// to your skill. Alexa allows anywhere from 60 seconds to 24 hours. The default is one hour.
+func WithExpiration(expiresAfter time.Duration) ClientOption {
+	return func(client *Client) {
+		client.expiresAfter = expiresAfter
+	}
+}
+
+// WithTokenSource supplies the source of access tokens rather than having the client fetch its own
+// using the client id/secret, which are ignored (and can be blank) when you use this. This lets you
+// share one set of cached tokens across all of your API clients (also see `NewTokenSourceClient()`).
+func WithTokenSource(tokens oauth2.TokenSource) ClientOption {
+	return func(client *Client) {
+		client.tokens = tokens
+	}
+}
+
+// WithHTTPClient overrides the HTTP client used to communicate with Amazon (e.g. to change timeouts).
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(client *Client) {
+		client.httpClient = httpClient
+	}
+}
+
+// Client sends skill messages to the Alexa API on behalf of your skill.
+type Client struct {
+	apiURL       string
+	tokenURL     string
+	expiresAfter time.Duration
+	httpClient   *http.Client
+	tokens       oauth2.TokenSource
+}
+
+// Send delivers the message data to your skill on behalf of the given user. The data can be any
+// value that marshals to a JSON object; your skill receives it as the "message" of the request.
+func (client *Client) Send(ctx context.Context, userID string, data interface{}) error {
+	if userID == "" {
+		return fmt.Errorf("messaging: missing user id")
+	}
+
+	token, err := lwa.AccessToken(ctx, client.tokens)
+	if err != nil {
+		return fmt.Errorf("messaging: %v", err)
+	}
+
+	body, err := json.Marshal(struct {
+		Data                interface{} `json:"data"`
+		ExpiresAfterSeconds int         `json:"expiresAfterSeconds"`
+	}{
+		Data:                data,
+		ExpiresAfterSeconds: int(client.expiresAfter / time.Second),
+	})
+	if err != nil {
+		return fmt.Errorf("messaging: encode message: %v", err)
+	}
+
+	request, err := http.NewRequest(http.MethodPost, client.messagesURL(userID), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("messaging: send message: %v", err)
+	}
+	request = request.WithContext(ctx)
+	request.Header.Set("Authorization", "Bearer "+token)
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := client.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("messaging: send message: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		message, _ := ioutil.ReadAll(response.Body)
+		return fmt.Errorf("messaging: send message: %s: %s", response.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+func (client *Client) messagesURL(userID string) string {
+	return client.apiURL + "/v1/skillmessages/users/" + url.PathEscape(userID)
+}
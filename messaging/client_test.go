@@ -0,0 +1,84 @@
+package messaging_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/robsignorelli/golexa/messaging"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/oauth2"
+)
+
+func TestClientSuite(t *testing.T) {
+	suite.Run(t, new(ClientSuite))
+}
+
+type ClientSuite struct {
+	suite.Suite
+}
+
+func (suite ClientSuite) TestSend() {
+	var tokenScope, messagePath, messageAuth string
+	var messageJSON map[string]interface{}
+	messageStatus := http.StatusAccepted
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth/o2/token" {
+			_ = r.ParseForm()
+			tokenScope = r.PostForm.Get("scope")
+			_, _ = w.Write([]byte(`{"access_token":"token.123","expires_in":3600,"token_type":"bearer"}`))
+			return
+		}
+		messagePath = r.URL.Path
+		messageAuth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&messageJSON)
+		w.WriteHeader(messageStatus)
+	}))
+	defer server.Close()
+
+	client := messaging.NewClient("client.id", "client.secret",
+		messaging.WithAPIURL(server.URL),
+		messaging.WithTokenURL(server.URL+"/auth/o2/token"),
+		messaging.WithExpiration(5*time.Minute))
+
+	suite.Error(client.Send(context.TODO(), "", map[string]string{"foo": "bar"}),
+		"Should fail when there's no user id")
+
+	err := client.Send(context.TODO(), "user.123", map[string]string{"foo": "bar"})
+	suite.Require().NoError(err,
+		"Should send messages successfully when the API accepts them")
+	suite.Equal("alexa:skill_messaging", tokenScope,
+		"Should request the skill messaging scope")
+	suite.Equal("/v1/skillmessages/users/user.123", messagePath,
+		"Should send the message to the user's endpoint")
+	suite.Equal("Bearer token.123", messageAuth,
+		"Should authorize the request using the LWA token")
+	suite.Equal(map[string]interface{}{"foo": "bar"}, messageJSON["data"],
+		"Should include the message data")
+	suite.Equal(float64(300), messageJSON["expiresAfterSeconds"],
+		"Should include the message expiration")
+
+	messageStatus = http.StatusForbidden
+	suite.Error(client.Send(context.TODO(), "user.123", map[string]string{"foo": "bar"}),
+		"Should fail when the API rejects the message")
+}
+
+func (suite ClientSuite) TestTokenSourceClient() {
+	var messageAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		messageAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client := messaging.NewTokenSourceClient(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "shared.123"}),
+		messaging.WithAPIURL(server.URL))
+	suite.Require().NoError(client.Send(context.TODO(), "user.123", map[string]string{"foo": "bar"}),
+		"Should send messages w/o a client id/secret")
+	suite.Equal("Bearer shared.123", messageAuth,
+		"Should authorize the request using the shared token source")
+}
@@ -0,0 +1,309 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256" // Register the hashes for the supported signing algorithms.
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JWKSValidator checks tokens that are JWTs signed by your OAuth provider. The signature is verified
+// using the public keys published at the given JSON Web Key Set URL, and the token is invalid if it has
+// expired, has no expiration (see JWTAllowNoExpiration()), or isn't valid yet. Use JWTIssuer() and JWTAudience() to also verify who issued the token
+// and who it was meant for. RS256/384/512 and ES256/384/512 signatures are supported.
+func JWKSValidator(jwksURL string, options ...JWTOption) TokenValidator {
+	v := &jwksValidator{
+		jwksURL:    jwksURL,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		keys:       map[string]crypto.PublicKey{},
+	}
+	for _, opt := range options {
+		opt(v)
+	}
+	return v
+}
+
+// JWTOption adds additional verification rules to a JWKSValidator.
+type JWTOption func(*jwksValidator)
+
+// JWTIssuer requires that the token's "iss" claim matches the given issuer.
+func JWTIssuer(issuer string) JWTOption {
+	return func(v *jwksValidator) {
+		v.issuer = issuer
+	}
+}
+
+// JWTAudience requires that the token's "aud" claim contains the given audience.
+func JWTAudience(audience string) JWTOption {
+	return func(v *jwksValidator) {
+		v.audience = audience
+	}
+}
+
+// JWTAllowNoExpiration accepts tokens that don't have an "exp" claim. By default, we reject them since
+// they'd be valid forever, so only use this if your provider really doesn't expire its tokens.
+func JWTAllowNoExpiration() JWTOption {
+	return func(v *jwksValidator) {
+		v.allowNoExpiration = true
+	}
+}
+
+// jwksRefreshInterval keeps a flood of tokens w/ unknown key ids from hammering the JWKS endpoint.
+const jwksRefreshInterval = time.Minute
+
+type jwksValidator struct {
+	jwksURL           string
+	issuer            string
+	audience          string
+	allowNoExpiration bool
+	httpClient        *http.Client
+
+	mutex       sync.Mutex
+	keys        map[string]crypto.PublicKey
+	lastRefresh time.Time
+}
+
+func (v *jwksValidator) ValidateToken(ctx context.Context, accessToken string) (AccountClaims, error) {
+	parts := strings.Split(accessToken, ".")
+	if len(parts) != 3 {
+		return AccountClaims{}, ErrInvalidToken
+	}
+
+	header := struct {
+		Algorithm string `json:"alg"`
+		KeyID     string `json:"kid"`
+	}{}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return AccountClaims{}, ErrInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return AccountClaims{}, ErrInvalidToken
+	}
+
+	key, err := v.key(ctx, header.KeyID)
+	if err != nil {
+		return AccountClaims{}, err
+	}
+	if key == nil {
+		return AccountClaims{}, ErrInvalidToken
+	}
+	if !verifySignature(header.Algorithm, key, parts[0]+"."+parts[1], signature) {
+		return AccountClaims{}, ErrInvalidToken
+	}
+
+	raw := map[string]interface{}{}
+	if err := decodeSegment(parts[1], &raw); err != nil {
+		return AccountClaims{}, ErrInvalidToken
+	}
+	if !v.validClaims(raw) {
+		return AccountClaims{}, ErrInvalidToken
+	}
+	return claimsFromMap(raw), nil
+}
+
+func (v *jwksValidator) validClaims(raw map[string]interface{}) bool {
+	now := time.Now()
+	exp, ok := raw["exp"].(float64)
+	if !ok && !v.allowNoExpiration {
+		return false
+	}
+	if ok && now.After(time.Unix(int64(exp), 0)) {
+		return false
+	}
+	if nbf, ok := raw["nbf"].(float64); ok && now.Before(time.Unix(int64(nbf), 0)) {
+		return false
+	}
+	if v.issuer != "" && raw["iss"] != v.issuer {
+		return false
+	}
+	if v.audience != "" {
+		switch aud := raw["aud"].(type) {
+		case string:
+			return aud == v.audience
+		case []interface{}:
+			for _, value := range aud {
+				if value == v.audience {
+					return true
+				}
+			}
+			return false
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// key finds the public key w/ the given id, refreshing the key set if we don't know about it
+// yet (e.g. your provider rotated its keys). A nil key means there is no such key. If we refreshed
+// the keys too recently to try again, we can't say for sure whether the token is invalid, so that's
+// a transient error rather than ErrInvalidToken (which RequireAccount caches).
+func (v *jwksValidator) key(ctx context.Context, keyID string) (crypto.PublicKey, error) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if key, ok := v.keys[keyID]; ok {
+		return key, nil
+	}
+	if time.Since(v.lastRefresh) < jwksRefreshInterval {
+		return nil, fmt.Errorf("jwks: unknown key id '%s' and the key set was refreshed too recently to try again", keyID)
+	}
+
+	keys, err := v.fetchKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v.keys = keys
+	v.lastRefresh = time.Now()
+	return v.keys[keyID], nil
+}
+
+func (v *jwksValidator) fetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	request, err := http.NewRequest(http.MethodGet, v.jwksURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("jwks: %v", err)
+	}
+	request = request.WithContext(ctx)
+
+	response, err := v.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("jwks: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("jwks: %s", response.Status)
+	}
+
+	keySet := struct {
+		Keys []jsonWebKey `json:"keys"`
+	}{}
+	if err := json.NewDecoder(response.Body).Decode(&keySet); err != nil {
+		return nil, fmt.Errorf("jwks: %v", err)
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, jwk := range keySet.Keys {
+		// Skip key types/curves that we don't support rather than failing the entire set.
+		if key := jwk.publicKey(); key != nil {
+			keys[jwk.KeyID] = key
+		}
+	}
+	return keys, nil
+}
+
+type jsonWebKey struct {
+	KeyID   string `json:"kid"`
+	KeyType string `json:"kty"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+func (jwk jsonWebKey) publicKey() crypto.PublicKey {
+	switch jwk.KeyType {
+	case "RSA":
+		n, nErr := base64.RawURLEncoding.DecodeString(jwk.N)
+		e, eErr := base64.RawURLEncoding.DecodeString(jwk.E)
+		if nErr != nil || eErr != nil {
+			return nil
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil
+		}
+		x, xErr := base64.RawURLEncoding.DecodeString(jwk.X)
+		y, yErr := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if xErr != nil || yErr != nil {
+			return nil
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+	default:
+		return nil
+	}
+}
+
+func verifySignature(algorithm string, key crypto.PublicKey, signingInput string, signature []byte) bool {
+	if len(algorithm) != 5 {
+		return false
+	}
+
+	var hash crypto.Hash
+	switch algorithm[2:] {
+	case "256":
+		hash = crypto.SHA256
+	case "384":
+		hash = crypto.SHA384
+	case "512":
+		hash = crypto.SHA512
+	default:
+		return false
+	}
+	hasher := hash.New()
+	hasher.Write([]byte(signingInput))
+	digest := hasher.Sum(nil)
+
+	switch publicKey := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(algorithm, "RS") {
+			return false
+		}
+		return rsa.VerifyPKCS1v15(publicKey, hash, digest, signature) == nil
+	case *ecdsa.PublicKey:
+		// The signature is the fixed-size r and s values, and each alg only works w/ its own curve.
+		size := (publicKey.Curve.Params().BitSize + 7) / 8
+		if !strings.HasPrefix(algorithm, "ES") || len(signature) != 2*size || ecdsaCurves[algorithm] != publicKey.Curve.Params().Name {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:len(signature)/2])
+		s := new(big.Int).SetBytes(signature[len(signature)/2:])
+		return ecdsa.Verify(publicKey, digest, r, s)
+	default:
+		return false
+	}
+}
+
+// ecdsaCurves are the curves that each of the ES algorithms must be used with.
+var ecdsaCurves = map[string]string{
+	"ES256": "P-256",
+	"ES384": "P-384",
+	"ES512": "P-521",
+}
+
+func decodeSegment(segment string, value interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
@@ -10,7 +10,7 @@ import (
 
 // Logger creates a middleware function that logs the start/end of every single utterance you handle
 // in your skill. We include all of the "important" things that you probably care about: user/device id,
-// elapsed time, request id, etc. You can optionally have the logger spit out the entire JSON of the
+// elapsed time, request id, any error reported by `Response.Err()`, etc. You can optionally have the logger spit out the entire JSON of the
 // incoming requests as well as what you actually output.
 func Logger(options ...LoggerOption) golexa.MiddlewareFunc {
 	logger := loggerMiddleware{}
@@ -88,6 +88,11 @@ func (logger loggerMiddleware) logFinished(request golexa.Request, response gole
 	if err != nil {
 		entry = entry.WithField("error", err)
 	}
+	// The response builder doesn't log the problems it ran into (e.g. a template that didn't
+	// evaluate), so report them here unless the handler already returned it as the error.
+	if responseErr := response.Err(); responseErr != nil && responseErr != err {
+		entry = entry.WithField("response.error", responseErr)
+	}
 	if speak := response.Body.OutputSpeech; logger.IncludeSpeech && speak != nil {
 		entry = entry.WithField("response.speech", speak.SSML)
 	}
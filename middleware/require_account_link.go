@@ -2,47 +2,122 @@ package middleware
 
 import (
 	"context"
+	"time"
 
 	"github.com/robsignorelli/golexa"
 	"github.com/robsignorelli/golexa/speech"
 	"github.com/sirupsen/logrus"
 )
 
+// RequireAccount creates a middleware function that denies access to users that have not linked
+// their Amazon account with your system. Out of the box, it only checks that the request has an access
+// token, but you can use RequireAccountValidator() to also verify that the token is still valid. When
+// the user is not linked, we respond with your template and a LinkAccount card in their Alexa app.
 func RequireAccount(options ...RequireAccountOption) golexa.MiddlewareFunc {
 	// I really don't expect you to use this text out of the box, but if you want, it's up to you.
 	r := requireAccount{
 		template: speech.NewTemplate("I'm sorry. You must connect your account using the Alexa app in order to use this feature."),
+		cacheTTL: 5 * time.Minute,
 	}
 	for _, opt := range options {
 		opt(&r)
 	}
+	if r.validator != nil {
+		r.cache = newClaimsCache(r.cacheTTL)
+	}
 	return r.checkAccessToken
 }
 
+// TemplateDataAccountClaims is the key for the user's AccountClaims in the template data when you
+// use RequireAccount w/ a validator, so your templates can use "{{.Data.accountClaims.Subject}}".
+const TemplateDataAccountClaims = "accountClaims"
+
+// RequireAccountOption tweaks the settings of your account linking middleware. Please use the
+// built-in helpers like RequireAccountTemplate() and RequireAccountValidator().
 type RequireAccountOption func(*requireAccount)
 
+// RequireAccountTemplate changes what Alexa says to users that have not linked their account. The
+// template has access to the request via "{{.Request}}" if you need any info about the user.
+//
+// Deprecated behavior: the request is also passed as the template's value, so older templates that use
+// "{{.Value.Session.User.ID}}" keep working. Please switch those over to "{{.Request...}}" since
+// "{{.Value}}" will be nil in a future release.
 func RequireAccountTemplate(t speech.Template) RequireAccountOption {
 	return func(r *requireAccount) {
 		r.template = t
 	}
 }
 
+// RequireAccountValidator verifies the user's access token with your OAuth provider before allowing
+// the request through, so expired or revoked tokens are treated the same as unlinked accounts. Use
+// one of the built-in validators like IntrospectionValidator() or JWKSValidator(), or supply your
+// own using TokenValidatorFunc. The resulting claims are available to your handlers via AccountClaimsFrom().
+func RequireAccountValidator(validator TokenValidator) RequireAccountOption {
+	return func(r *requireAccount) {
+		r.validator = validator
+	}
+}
+
+// RequireAccountCacheTTL determines how long we remember the result of validating a token so that
+// we don't hit your OAuth provider on every single request. We will never cache a valid token beyond
+// its own expiration. The default is 5 minutes; use 0 to disable caching altogether.
+func RequireAccountCacheTTL(ttl time.Duration) RequireAccountOption {
+	return func(r *requireAccount) {
+		r.cacheTTL = ttl
+	}
+}
+
 type requireAccount struct {
-	template speech.Template
+	template  speech.Template
+	validator TokenValidator
+	cacheTTL  time.Duration
+	cache     *claimsCache
 }
 
 func (r requireAccount) checkAccessToken(ctx context.Context, request golexa.Request, next golexa.HandlerFunc) (golexa.Response, error) {
-	if request.Context.System.User.AccessToken != "" {
+	accessToken := request.Context.System.User.AccessToken
+	if accessToken == "" {
+		return r.linkAccount(request, "Missing user access token")
+	}
+	if r.validator == nil {
 		return next(ctx, request)
 	}
 
+	claims, err := r.validate(ctx, accessToken)
+	switch {
+	case err == ErrInvalidToken:
+		return r.linkAccount(request, "Invalid user access token")
+	case err != nil:
+		return golexa.Fail("golexa: unable to validate access token: " + err.Error())
+	default:
+		return next(WithAccountClaims(ctx, claims), request.WithData(TemplateDataAccountClaims, claims))
+	}
+}
+
+// validate checks the token against the validator, using/storing cached results if possible.
+func (r requireAccount) validate(ctx context.Context, accessToken string) (AccountClaims, error) {
+	if entry, ok := r.cache.get(accessToken); ok {
+		return entry.claims, entry.err
+	}
+
+	claims, err := r.validator.ValidateToken(ctx, accessToken)
+	if err != nil && err != ErrInvalidToken {
+		// Don't cache transient failures like your OAuth provider being down.
+		return claims, err
+	}
+	r.cache.put(accessToken, claims, err)
+	return claims, err
+}
+
+func (r requireAccount) linkAccount(request golexa.Request, reason string) (golexa.Response, error) {
 	logrus.WithField("label", "golexa").
 		WithField("request.id", request.Body.RequestID).
 		WithField("user.id", request.Context.System.User.ID).
 		WithField("device.id", request.Context.System.Device.ID).
-		Info("Missing user access token")
+		Info(reason)
 
 	return golexa.NewResponse(request).
 		SpeakTemplate(r.template, request).
+		LinkAccountCard().
 		Ok()
 }
@@ -0,0 +1,340 @@
+package middleware_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/robsignorelli/golexa"
+	"github.com/robsignorelli/golexa/middleware"
+	"github.com/robsignorelli/golexa/speech"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestRequireAccountSuite(t *testing.T) {
+	suite.Run(t, new(RequireAccountSuite))
+}
+
+type RequireAccountSuite struct {
+	suite.Suite
+}
+
+// run sends a request w/ the given access token through the middleware, returning the response
+// as well as the subject that the final handler saw (blank if the handler never ran).
+func (suite RequireAccountSuite) run(mw golexa.MiddlewareFunc, accessToken string) (golexa.Response, string, error) {
+	subject := ""
+	handler := golexa.Middleware{mw}.Then(func(ctx context.Context, request golexa.Request) (golexa.Response, error) {
+		subject = "<none>"
+		if claims, ok := middleware.AccountClaimsFrom(ctx); ok {
+			subject = claims.Subject
+		}
+		return golexa.NewResponse(request).Speak("Handled").Ok()
+	})
+
+	request := golexa.NewIntentRequest("Foo", golexa.NewSlots())
+	request.Context.System.User.AccessToken = accessToken
+	res, err := handler(context.TODO(), request)
+	return res, subject, err
+}
+
+func (suite RequireAccountSuite) TestMissingToken() {
+	res, subject, err := suite.run(middleware.RequireAccount(), "")
+	suite.NoError(err,
+		"Should not fail when the user has not linked their account")
+	suite.Equal("", subject,
+		"Should not invoke the handler when there's no access token")
+	suite.Require().NotNil(res.Body.Card,
+		"Should include a card prompting the user to link their account")
+	suite.Equal("LinkAccount", res.Body.Card.Type,
+		"Should include a card prompting the user to link their account")
+
+	res, subject, err = suite.run(middleware.RequireAccount(), "token.123")
+	suite.NoError(err,
+		"Should not fail when the user has an access token")
+	suite.Equal("<none>", subject,
+		"Should invoke the handler w/o claims when there's no validator")
+}
+
+func (suite RequireAccountSuite) TestValidatorFunc() {
+	calls := 0
+	validator := middleware.TokenValidatorFunc(func(ctx context.Context, accessToken string) (middleware.AccountClaims, error) {
+		calls++
+		switch accessToken {
+		case "valid":
+			return middleware.AccountClaims{Subject: "user.123"}, nil
+		case "invalid":
+			return middleware.AccountClaims{}, middleware.ErrInvalidToken
+		default:
+			return middleware.AccountClaims{}, errors.New("provider is down")
+		}
+	})
+	mw := middleware.RequireAccount(middleware.RequireAccountValidator(validator))
+
+	_, subject, err := suite.run(mw, "valid")
+	suite.NoError(err,
+		"Should not fail when the token is valid")
+	suite.Equal("user.123", subject,
+		"Should expose the resolved claims to the handler")
+
+	res, subject, err := suite.run(mw, "invalid")
+	suite.NoError(err,
+		"Should not fail when the token is invalid")
+	suite.Equal("", subject,
+		"Should not invoke the handler when the token is invalid")
+	suite.Equal("LinkAccount", res.Body.Card.Type,
+		"Should prompt the user to re-link their account when the token is invalid")
+
+	_, subject, err = suite.run(mw, "error")
+	suite.Error(err,
+		"Should fail when the token could not be validated")
+	suite.Equal("", subject,
+		"Should not invoke the handler when the token could not be validated")
+
+	_, _, _ = suite.run(mw, "valid")
+	_, _, _ = suite.run(mw, "invalid")
+	_, _, _ = suite.run(mw, "error")
+	suite.Equal(4, calls,
+		"Should cache valid/invalid results but not failures")
+
+	calls = 0
+	mw = middleware.RequireAccount(
+		middleware.RequireAccountValidator(validator),
+		middleware.RequireAccountCacheTTL(0))
+	_, _, _ = suite.run(mw, "valid")
+	_, _, _ = suite.run(mw, "valid")
+	suite.Equal(2, calls,
+		"Should not cache anything when the TTL is 0")
+}
+
+func (suite RequireAccountSuite) TestIntrospectionValidator() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, secret, _ := r.BasicAuth(); id != "client.id" || secret != "client.secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = r.ParseForm()
+		if r.PostForm.Get("token") != "valid" {
+			_, _ = w.Write([]byte(`{"active": false}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"active": true, "sub": "user.123", "scope": "read write"}`))
+	}))
+	defer server.Close()
+
+	validator := middleware.IntrospectionValidator(server.URL, "client.id", "client.secret")
+
+	claims, err := validator.ValidateToken(context.TODO(), "valid")
+	suite.Require().NoError(err,
+		"Should accept active tokens")
+	suite.Equal("user.123", claims.Subject,
+		"Should extract the subject")
+	suite.True(claims.HasScope("write"),
+		"Should extract the scopes")
+
+	_, err = validator.ValidateToken(context.TODO(), "expired")
+	suite.Equal(middleware.ErrInvalidToken, err,
+		"Should reject inactive tokens")
+
+	validator = middleware.IntrospectionValidator(server.URL, "client.id", "wrong")
+	_, err = validator.ValidateToken(context.TODO(), "valid")
+	suite.Error(err,
+		"Should fail when the introspection endpoint rejects us")
+	suite.NotEqual(middleware.ErrInvalidToken, err,
+		"Should not treat failures to validate as invalid tokens")
+}
+
+func (suite RequireAccountSuite) TestJWKSValidator() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	suite.Require().NoError(err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": "key.1",
+				"kty": "RSA",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	defer server.Close()
+
+	sign := func(keyID string, claims map[string]interface{}) string {
+		header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": keyID})
+		payload, _ := json.Marshal(claims)
+		input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+		digest := sha256.Sum256([]byte(input))
+		signature, _ := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+	}
+	future := time.Now().Add(time.Hour).Unix()
+	past := time.Now().Add(-time.Hour).Unix()
+
+	validator := middleware.JWKSValidator(server.URL,
+		middleware.JWTIssuer("https://auth.example.com"),
+		middleware.JWTAudience("my-skill"))
+
+	claims, err := validator.ValidateToken(context.TODO(), sign("key.1", map[string]interface{}{
+		"sub": "user.123", "exp": future, "iss": "https://auth.example.com", "aud": []string{"my-skill"},
+	}))
+	suite.Require().NoError(err,
+		"Should accept properly signed tokens")
+	suite.Equal("user.123", claims.Subject,
+		"Should extract the subject")
+	suite.Equal(future, claims.ExpiresAt.Unix(),
+		"Should extract the expiration")
+
+	_, err = validator.ValidateToken(context.TODO(), sign("key.1", map[string]interface{}{
+		"sub": "user.123", "exp": past, "iss": "https://auth.example.com", "aud": "my-skill",
+	}))
+	suite.Equal(middleware.ErrInvalidToken, err,
+		"Should reject expired tokens")
+
+	_, err = validator.ValidateToken(context.TODO(), sign("key.1", map[string]interface{}{
+		"sub": "user.123", "exp": future, "iss": "https://evil.example.com", "aud": "my-skill",
+	}))
+	suite.Equal(middleware.ErrInvalidToken, err,
+		"Should reject tokens from other issuers")
+
+	_, err = validator.ValidateToken(context.TODO(), sign("key.1", map[string]interface{}{
+		"sub": "user.123", "exp": future, "iss": "https://auth.example.com", "aud": "other-skill",
+	}))
+	suite.Equal(middleware.ErrInvalidToken, err,
+		"Should reject tokens meant for other audiences")
+
+	_, err = validator.ValidateToken(context.TODO(), sign("key.1", map[string]interface{}{
+		"sub": "user.123", "iss": "https://auth.example.com", "aud": "my-skill",
+	}))
+	suite.Equal(middleware.ErrInvalidToken, err,
+		"Should reject tokens w/o an expiration")
+	_, err = middleware.JWKSValidator(server.URL, middleware.JWTAllowNoExpiration()).
+		ValidateToken(context.TODO(), sign("key.1", map[string]interface{}{"sub": "user.123"}))
+	suite.NoError(err,
+		"Should accept tokens w/o an expiration when you allow them")
+
+	unknownKey := sign("key.2", map[string]interface{}{
+		"sub": "user.123", "exp": future, "iss": "https://auth.example.com", "aud": "my-skill",
+	})
+	_, err = middleware.JWKSValidator(server.URL).ValidateToken(context.TODO(), unknownKey)
+	suite.Equal(middleware.ErrInvalidToken, err,
+		"Should reject tokens signed by keys that aren't in a freshly fetched key set")
+	_, err = validator.ValidateToken(context.TODO(), unknownKey)
+	suite.Error(err,
+		"Should fail tokens signed by unknown keys when we can't refresh the key set yet")
+	suite.NotEqual(middleware.ErrInvalidToken, err,
+		"Should not claim that tokens are invalid when the provider might have just rotated its keys")
+
+	token := sign("key.1", map[string]interface{}{
+		"sub": "user.123", "exp": future, "iss": "https://auth.example.com", "aud": "my-skill",
+	})
+	_, err = validator.ValidateToken(context.TODO(), token[:len(token)-4]+"AAAA")
+	suite.Equal(middleware.ErrInvalidToken, err,
+		"Should reject tokens w/ bad signatures")
+
+	_, err = validator.ValidateToken(context.TODO(), "opaque-token")
+	suite.Equal(middleware.ErrInvalidToken, err,
+		"Should reject tokens that aren't JWTs")
+}
+
+func (suite RequireAccountSuite) TestJWKSValidator_ECDSA() {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	suite.Require().NoError(err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": "key.1",
+				"kty": "EC",
+				"crv": "P-256",
+				"x":   base64.RawURLEncoding.EncodeToString(key.X.Bytes()),
+				"y":   base64.RawURLEncoding.EncodeToString(key.Y.Bytes()),
+			}},
+		})
+	}))
+	defer server.Close()
+
+	// sign always hashes w/ SHA-256, so we can lie about the alg in the header.
+	sign := func(algorithm string, pad bool) string {
+		header, _ := json.Marshal(map[string]string{"alg": algorithm, "typ": "JWT", "kid": "key.1"})
+		payload, _ := json.Marshal(map[string]interface{}{"sub": "user.123", "exp": time.Now().Add(time.Hour).Unix()})
+		input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+		digest := sha256.Sum256([]byte(input))
+		r, s, _ := ecdsa.Sign(rand.Reader, key, digest[:])
+
+		size := 32
+		if pad {
+			size = 48
+		}
+		signature := make([]byte, 2*size)
+		copy(signature[size-len(r.Bytes()):size], r.Bytes())
+		copy(signature[2*size-len(s.Bytes()):], s.Bytes())
+		return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+	}
+
+	validator := middleware.JWKSValidator(server.URL)
+	claims, err := validator.ValidateToken(context.TODO(), sign("ES256", false))
+	suite.Require().NoError(err,
+		"Should accept properly signed ES256 tokens")
+	suite.Equal("user.123", claims.Subject,
+		"Should extract the subject")
+
+	_, err = validator.ValidateToken(context.TODO(), sign("ES256", true))
+	suite.Equal(middleware.ErrInvalidToken, err,
+		"Should reject signatures that aren't exactly twice the curve's size")
+	_, err = validator.ValidateToken(context.TODO(), sign("ES384", false))
+	suite.Equal(middleware.ErrInvalidToken, err,
+		"Should reject algorithms that don't match the key's curve")
+}
+
+func (suite RequireAccountSuite) TestTemplateData() {
+	validator := middleware.TokenValidatorFunc(func(ctx context.Context, accessToken string) (middleware.AccountClaims, error) {
+		return middleware.AccountClaims{Subject: "user.123"}, nil
+	})
+	welcome := speech.NewTemplate("Welcome back, {{.Data.accountClaims.Subject}}")
+	handler := golexa.Middleware{middleware.RequireAccount(middleware.RequireAccountValidator(validator))}.
+		Then(func(ctx context.Context, request golexa.Request) (golexa.Response, error) {
+			return golexa.NewResponse(request).SpeakTemplate(welcome, nil).Ok()
+		})
+
+	request := golexa.NewIntentRequest("Foo", golexa.NewSlots())
+	request.Context.System.User.AccessToken = "valid"
+	res, err := handler(context.TODO(), request)
+	suite.Require().NoError(err,
+		"Should not fail when the token is valid")
+	suite.Equal("<speak>Welcome back, user.123</speak>", res.Body.OutputSpeech.SSML,
+		"Should make the claims available to templates")
+}
+
+func (suite RequireAccountSuite) TestCustomTemplate() {
+	for _, text := range []string{
+		"Link your account, {{.Request.Session.User.ID}}",
+		"Link your account, {{.Value.Session.User.ID}}",
+	} {
+		mw := middleware.RequireAccount(middleware.RequireAccountTemplate(speech.NewTemplate(text)))
+		handler := golexa.Middleware{mw}.Then(func(ctx context.Context, request golexa.Request) (golexa.Response, error) {
+			return golexa.NewResponse(request).Speak("Handled").Ok()
+		})
+
+		request := golexa.NewIntentRequest("Foo", golexa.NewSlots())
+		request.Session.User.ID = "user.123"
+		res, err := handler(context.TODO(), request)
+		suite.Require().NoError(err,
+			"Should not fail when the user has not linked their account")
+		suite.Equal("<speak>Link your account, user.123</speak>", res.Body.OutputSpeech.SSML,
+			"Should give the template access to the request: "+text)
+		suite.NoError(res.Err(),
+			"Should evaluate the template w/o errors: "+text)
+	}
+}
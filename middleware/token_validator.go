@@ -0,0 +1,211 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidToken is what your TokenValidator should return when the user's access token is expired,
+// revoked, or otherwise unacceptable. Any other error is treated as a failure to validate the token (e.g.
+// your OAuth provider is down) rather than a reason to have the user re-link their account.
+var ErrInvalidToken = errors.New("invalid access token")
+
+// TokenValidator verifies an account-linked access token with your OAuth provider.
+type TokenValidator interface {
+	ValidateToken(ctx context.Context, accessToken string) (AccountClaims, error)
+}
+
+// TokenValidatorFunc lets you use a plain function as a TokenValidator when the built-in
+// introspection and JWKS validators don't fit your OAuth provider.
+type TokenValidatorFunc func(ctx context.Context, accessToken string) (AccountClaims, error)
+
+// ValidateToken simply invokes the function.
+func (f TokenValidatorFunc) ValidateToken(ctx context.Context, accessToken string) (AccountClaims, error) {
+	return f(ctx, accessToken)
+}
+
+// AccountClaims is what your OAuth provider told us about the user's access token.
+type AccountClaims struct {
+	// Subject is the id of the user in your system that the token belongs to.
+	Subject string
+	// Scopes are the permissions the user granted to the token.
+	Scopes []string
+	// ExpiresAt is when the token expires (zero if your provider didn't tell us).
+	ExpiresAt time.Time
+	// Raw contains all of the claims/fields exactly as your provider gave them to us.
+	Raw map[string]interface{}
+}
+
+// HasScope returns true if the token was granted the given scope.
+func (claims AccountClaims) HasScope(scope string) bool {
+	for _, s := range claims.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type accountClaimsKey struct{}
+
+// WithAccountClaims decorates the context with the claims for the current user's access token. The
+// RequireAccount middleware does this for you when you use a validator, but this can be handy
+// for setting up unit tests for your handlers.
+func WithAccountClaims(ctx context.Context, claims AccountClaims) context.Context {
+	return context.WithValue(ctx, accountClaimsKey{}, claims)
+}
+
+// AccountClaimsFrom fetches the claims that RequireAccount resolved for the user's access token. The
+// boolean is false if the request did not go through RequireAccount with a validator.
+func AccountClaimsFrom(ctx context.Context) (AccountClaims, bool) {
+	claims, ok := ctx.Value(accountClaimsKey{}).(AccountClaims)
+	return claims, ok
+}
+
+// AccountSubject is a shorthand to fetch the id of the user in your system from the resolved claims.
+func AccountSubject(ctx context.Context) string {
+	claims, _ := AccountClaimsFrom(ctx)
+	return claims.Subject
+}
+
+// IntrospectionValidator checks tokens using your OAuth provider's RFC 7662 token introspection endpoint,
+// authenticating w/ the given client id/secret. Tokens that are not "active" are invalid.
+//
+// See: https://tools.ietf.org/html/rfc7662
+func IntrospectionValidator(endpoint, clientID, clientSecret string) TokenValidator {
+	return introspectionValidator{
+		endpoint:     endpoint,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		httpClient:   &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+type introspectionValidator struct {
+	endpoint     string
+	clientID     string
+	clientSecret string
+	httpClient   *http.Client
+}
+
+func (v introspectionValidator) ValidateToken(ctx context.Context, accessToken string) (AccountClaims, error) {
+	form := url.Values{}
+	form.Set("token", accessToken)
+	form.Set("token_type_hint", "access_token")
+
+	request, err := http.NewRequest(http.MethodPost, v.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return AccountClaims{}, fmt.Errorf("introspection: %v", err)
+	}
+	request = request.WithContext(ctx)
+	request.SetBasicAuth(v.clientID, v.clientSecret)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := v.httpClient.Do(request)
+	if err != nil {
+		return AccountClaims{}, fmt.Errorf("introspection: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		message, _ := ioutil.ReadAll(response.Body)
+		return AccountClaims{}, fmt.Errorf("introspection: %s: %s", response.Status, strings.TrimSpace(string(message)))
+	}
+
+	raw := map[string]interface{}{}
+	if err := json.NewDecoder(response.Body).Decode(&raw); err != nil {
+		return AccountClaims{}, fmt.Errorf("introspection: %v", err)
+	}
+	if active, _ := raw["active"].(bool); !active {
+		return AccountClaims{}, ErrInvalidToken
+	}
+
+	claims := claimsFromMap(raw)
+	if !claims.ExpiresAt.IsZero() && time.Now().After(claims.ExpiresAt) {
+		return AccountClaims{}, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// claimsFromMap extracts the standard claims that both introspection responses and JWTs share.
+func claimsFromMap(raw map[string]interface{}) AccountClaims {
+	claims := AccountClaims{Raw: raw}
+	claims.Subject, _ = raw["sub"].(string)
+	if scope, ok := raw["scope"].(string); ok {
+		claims.Scopes = strings.Fields(scope)
+	}
+	if exp, ok := raw["exp"].(float64); ok {
+		claims.ExpiresAt = time.Unix(int64(exp), 0)
+	}
+	return claims
+}
+
+// newClaimsCache creates a cache that remembers validation results for the given duration. A
+// TTL of 0 results in a nil cache, which simply never remembers anything.
+func newClaimsCache(ttl time.Duration) *claimsCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &claimsCache{ttl: ttl, entries: map[string]claimsCacheEntry{}}
+}
+
+type claimsCache struct {
+	ttl     time.Duration
+	mutex   sync.Mutex
+	entries map[string]claimsCacheEntry
+}
+
+type claimsCacheEntry struct {
+	claims    AccountClaims
+	err       error
+	expiresAt time.Time
+}
+
+func (cache *claimsCache) get(accessToken string) (claimsCacheEntry, bool) {
+	if cache == nil {
+		return claimsCacheEntry{}, false
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	entry, ok := cache.entries[accessToken]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return claimsCacheEntry{}, false
+	}
+	return entry, true
+}
+
+func (cache *claimsCache) put(accessToken string, claims AccountClaims, err error) {
+	if cache == nil {
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(cache.ttl)
+	if !claims.ExpiresAt.IsZero() && claims.ExpiresAt.Before(expiresAt) {
+		expiresAt = claims.ExpiresAt
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	// Every once in a while, clear out the stale entries so the cache doesn't grow forever.
+	if len(cache.entries) >= 1000 {
+		for key, entry := range cache.entries {
+			if now.After(entry.expiresAt) {
+				delete(cache.entries, key)
+			}
+		}
+	}
+	cache.entries[accessToken] = claimsCacheEntry{claims: claims, err: err, expiresAt: expiresAt}
+}
@@ -0,0 +1,277 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/robsignorelli/golexa/speech"
+	"github.com/robsignorelli/golexa/ssml"
+	"golang.org/x/text/language"
+)
+
+// Export generates the interaction model JSON for the given language; the same thing you'd find in
+// "skill-package/interactionModels/custom/en-US.json" of an ASK CLI project. Prompts are evaluated
+// using the speech templates' translations for that language, so they can't depend on any data. It
+// fails if your samples refer to slots you didn't declare or your slots use custom types you didn't declare.
+func (m Model) Export(lang language.Tag) ([]byte, error) {
+	if err := m.check(); err != nil {
+		return nil, err
+	}
+	prompts := promptBuilder{lang: lang}
+
+	doc := jsonFile{}
+	languageModel := &doc.InteractionModel.LanguageModel
+	if names := m.InvocationName.For(lang); len(names) > 0 {
+		languageModel.InvocationName = names[0]
+	}
+
+	for _, intent := range m.Intents {
+		languageModel.Intents = append(languageModel.Intents, exportIntent(intent, lang))
+
+		dialogIntent, ok, err := exportDialogIntent(intent, &prompts)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			if doc.InteractionModel.Dialog == nil {
+				doc.InteractionModel.Dialog = &jsonDialog{DelegationStrategy: "SKILL_RESPONSE"}
+			}
+			doc.InteractionModel.Dialog.Intents = append(doc.InteractionModel.Dialog.Intents, dialogIntent)
+		}
+	}
+
+	for _, slotType := range m.SlotTypes {
+		languageModel.Types = append(languageModel.Types, exportSlotType(slotType, lang))
+	}
+	doc.InteractionModel.Prompts = prompts.prompts
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+var patternSlotReference = regexp.MustCompile(`{([^}]+)}`)
+
+// check makes sure the model is internally consistent before we bother exporting it.
+func (m Model) check() error {
+	slotTypes := map[string]bool{}
+	for _, slotType := range m.SlotTypes {
+		slotTypes[slotType.Name] = true
+	}
+
+	for _, intent := range m.Intents {
+		slotNames := map[string]bool{}
+		for _, slot := range intent.Slots {
+			slotNames[slot.Name] = true
+			if !isBuiltInType(slot.Type) && !slotTypes[slot.Type] {
+				return fmt.Errorf("model: intent '%s': slot '%s' uses undeclared type '%s'", intent.Name, slot.Name, slot.Type)
+			}
+		}
+
+		for _, phrases := range intent.Samples.translations {
+			for _, sample := range phrases {
+				for _, match := range patternSlotReference.FindAllStringSubmatch(sample, -1) {
+					if !slotNames[match[1]] {
+						return fmt.Errorf("model: intent '%s': sample '%s' refers to undeclared slot '%s'", intent.Name, sample, match[1])
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func exportIntent(intent Intent, lang language.Tag) jsonIntent {
+	result := jsonIntent{
+		Name:    intent.Name,
+		Samples: nonNil(intent.Samples.For(lang)),
+		Slots:   []jsonSlot{},
+	}
+	for _, slot := range intent.Slots {
+		s := jsonSlot{
+			Name:    slot.Name,
+			Type:    slot.Type,
+			Samples: nonNil(slot.Samples.For(lang)),
+		}
+		if slot.Multiple {
+			s.MultipleValues = &jsonMultipleValues{Enabled: true}
+		}
+		result.Slots = append(result.Slots, s)
+	}
+	return result
+}
+
+// exportDialogIntent builds the dialog rules for the intent. The boolean is false if the intent
+// doesn't have any prompts, so there's no reason to include it in the dialog model.
+func exportDialogIntent(intent Intent, prompts *promptBuilder) (jsonDialogIntent, bool, error) {
+	hasDialog := intent.Confirmation != nil
+	result := jsonDialogIntent{
+		Name:                 intent.Name,
+		ConfirmationRequired: intent.Confirmation != nil,
+		Prompts:              map[string]string{},
+		Slots:                []jsonDialogSlot{},
+	}
+	if intent.Confirmation != nil {
+		id, err := prompts.add("Confirm.Intent."+intent.Name, *intent.Confirmation)
+		if err != nil {
+			return result, false, err
+		}
+		result.Prompts["confirmation"] = id
+	}
+
+	for _, slot := range intent.Slots {
+		s := jsonDialogSlot{
+			Name:                 slot.Name,
+			Type:                 slot.Type,
+			ElicitationRequired:  slot.Elicitation != nil,
+			ConfirmationRequired: slot.Confirmation != nil,
+			Prompts:              map[string]string{},
+		}
+		if slot.Elicitation != nil {
+			id, err := prompts.add("Elicit.Slot."+intent.Name+"."+slot.Name, *slot.Elicitation)
+			if err != nil {
+				return result, false, err
+			}
+			s.Prompts["elicitation"] = id
+			hasDialog = true
+		}
+		if slot.Confirmation != nil {
+			id, err := prompts.add("Confirm.Slot."+intent.Name+"."+slot.Name, *slot.Confirmation)
+			if err != nil {
+				return result, false, err
+			}
+			s.Prompts["confirmation"] = id
+			hasDialog = true
+		}
+		result.Slots = append(result.Slots, s)
+	}
+	return result, hasDialog, nil
+}
+
+func exportSlotType(slotType SlotType, lang language.Tag) jsonSlotType {
+	result := jsonSlotType{Name: slotType.Name, Values: []jsonSlotTypeValue{}}
+	for _, value := range slotType.Values {
+		names := value.Names.For(lang)
+		if len(names) == 0 {
+			continue
+		}
+		v := jsonSlotTypeValue{ID: value.ID}
+		v.Name.Value = names[0]
+		v.Name.Synonyms = names[1:]
+		result.Values = append(result.Values, v)
+	}
+	return result
+}
+
+// promptBuilder evaluates prompt templates for a single language, keeping track of their ids.
+type promptBuilder struct {
+	lang    language.Tag
+	prompts []jsonPrompt
+}
+
+// add evaluates every variant of the template so that Alexa can pick from all of them, just like
+// your skill does when it speaks the template. Anything w/ markup is exported as SSML.
+func (builder *promptBuilder) add(id string, template speech.Template) (string, error) {
+	texts, err := template.EvalVariants(speech.TemplateContext{Language: builder.lang})
+	if err != nil {
+		return "", fmt.Errorf("model: prompt '%s': %v", id, err)
+	}
+
+	variations := make([]jsonPromptVariation, len(texts))
+	for i, text := range texts {
+		doc, err := ssml.Wrap(text)
+		if err != nil {
+			return "", fmt.Errorf("model: prompt '%s': %v", id, err)
+		}
+		variations[i] = jsonPromptVariation{Type: "PlainText", Value: text}
+		if ssml.PlainText(doc) != ssml.PlainText(ssml.Escape(text)) {
+			variations[i] = jsonPromptVariation{Type: "SSML", Value: doc}
+		}
+	}
+	builder.prompts = append(builder.prompts, jsonPrompt{ID: id, Variations: variations})
+	return id, nil
+}
+
+// nonNil makes sure that empty lists are encoded as [] rather than null, which the ASK CLI rejects.
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// The JSON structure of an interaction model file that the ASK CLI and SMAPI understand.
+//
+// See: https://developer.amazon.com/en-US/docs/alexa/smapi/interaction-model-schema.html
+type jsonFile struct {
+	InteractionModel struct {
+		LanguageModel jsonLanguageModel `json:"languageModel"`
+		Dialog        *jsonDialog       `json:"dialog,omitempty"`
+		Prompts       []jsonPrompt      `json:"prompts,omitempty"`
+	} `json:"interactionModel"`
+}
+
+type jsonLanguageModel struct {
+	InvocationName string         `json:"invocationName"`
+	Intents        []jsonIntent   `json:"intents"`
+	Types          []jsonSlotType `json:"types,omitempty"`
+}
+
+type jsonIntent struct {
+	Name    string     `json:"name"`
+	Slots   []jsonSlot `json:"slots"`
+	Samples []string   `json:"samples"`
+}
+
+type jsonSlot struct {
+	Name           string              `json:"name"`
+	Type           string              `json:"type"`
+	Samples        []string            `json:"samples"`
+	MultipleValues *jsonMultipleValues `json:"multipleValues,omitempty"`
+}
+
+type jsonMultipleValues struct {
+	Enabled bool `json:"enabled"`
+}
+
+type jsonSlotType struct {
+	Name   string              `json:"name"`
+	Values []jsonSlotTypeValue `json:"values"`
+}
+
+type jsonSlotTypeValue struct {
+	ID   string `json:"id,omitempty"`
+	Name struct {
+		Value    string   `json:"value"`
+		Synonyms []string `json:"synonyms,omitempty"`
+	} `json:"name"`
+}
+
+type jsonDialog struct {
+	DelegationStrategy string             `json:"delegationStrategy,omitempty"`
+	Intents            []jsonDialogIntent `json:"intents"`
+}
+
+type jsonDialogIntent struct {
+	Name                 string            `json:"name"`
+	ConfirmationRequired bool              `json:"confirmationRequired"`
+	Prompts              map[string]string `json:"prompts"`
+	Slots                []jsonDialogSlot  `json:"slots"`
+}
+
+type jsonDialogSlot struct {
+	Name                 string            `json:"name"`
+	Type                 string            `json:"type"`
+	ElicitationRequired  bool              `json:"elicitationRequired"`
+	ConfirmationRequired bool              `json:"confirmationRequired"`
+	Prompts              map[string]string `json:"prompts"`
+}
+
+type jsonPrompt struct {
+	ID         string                `json:"id"`
+	Variations []jsonPromptVariation `json:"variations"`
+}
+
+type jsonPromptVariation struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
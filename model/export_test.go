@@ -0,0 +1,165 @@
+package model_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/robsignorelli/golexa/model"
+	"github.com/robsignorelli/golexa/speech"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/text/language"
+)
+
+func TestExportSuite(t *testing.T) {
+	suite.Run(t, new(ExportSuite))
+}
+
+type ExportSuite struct {
+	suite.Suite
+}
+
+// export generates the model JSON for the language and decodes it into a generic map that
+// is easy to poke around in.
+func (suite ExportSuite) export(m model.Model, lang language.Tag) map[string]interface{} {
+	data, err := m.Export(lang)
+	suite.Require().NoError(err, "Should export valid models")
+
+	doc := map[string]interface{}{}
+	suite.Require().NoError(json.Unmarshal(data, &doc), "Should export valid JSON")
+	return doc["interactionModel"].(map[string]interface{})
+}
+
+func (suite ExportSuite) TestPhrases() {
+	phrases := model.NewPhrases("hello", "hi").
+		WithTranslation(language.Spanish, "hola").
+		WithTranslation(language.MustParse("es-ES"), "buenas")
+
+	suite.Equal([]string{"hello", "hi"}, phrases.For(language.AmericanEnglish), "Should include the English phrases")
+	suite.Equal([]string{"buenas"}, phrases.For(language.MustParse("es-ES")), "Should include exact translations")
+	suite.Equal([]string{"hola"}, phrases.For(language.MustParse("es-MX")), "Should fall back to the parent language")
+	suite.Equal([]string{"hello", "hi"}, phrases.For(language.German), "Should fall back to English")
+
+	original := model.NewPhrases("a")
+	_ = original.WithTranslation(language.AmericanEnglish, "b")
+	suite.Equal([]string{"a"}, original.For(language.AmericanEnglish), "Should not modify the original phrases")
+}
+
+func (suite ExportSuite) TestExport() {
+	askForItem := speech.NewTemplate("What item?", speech.WithTranslation(language.Spanish, "¿Qué artículo?"))
+	areYouSure := speech.NewTemplate("<speak>Are you sure?</speak>")
+	m := model.Model{
+		InvocationName: model.NewPhrases("to do list").WithTranslation(language.Spanish, "lista de tareas"),
+		Intents: []model.Intent{
+			{Name: "AMAZON.StopIntent"},
+			{
+				Name:    "AddItem",
+				Samples: model.NewPhrases("add {item} to my list").WithTranslation(language.Spanish, "agrega {item}"),
+				Slots: []model.Slot{
+					{Name: "item", Type: "TodoItem", Elicitation: &askForItem, Multiple: true},
+					{Name: "count", Type: "AMAZON.NUMBER"},
+				},
+				Confirmation: &areYouSure,
+			},
+		},
+		SlotTypes: []model.SlotType{
+			{Name: "TodoItem", Values: []model.SlotTypeValue{
+				model.NewSlotTypeValue("laundry", "laundry", "wash clothes").WithTranslation(language.Spanish, "lavar la ropa"),
+				model.NewSlotTypeValue("dishes", "dishes"),
+			}},
+		},
+	}
+
+	doc := suite.export(m, language.MustParse("es-MX"))
+	languageModel := doc["languageModel"].(map[string]interface{})
+	suite.Equal("lista de tareas", languageModel["invocationName"], "Should use the translated invocation name")
+
+	intents := languageModel["intents"].([]interface{})
+	suite.Require().Len(intents, 2, "Should include all intents")
+	suite.Equal([]interface{}{}, intents[0].(map[string]interface{})["samples"], "Should use empty lists rather than null")
+
+	addItem := intents[1].(map[string]interface{})
+	suite.Equal([]interface{}{"agrega {item}"}, addItem["samples"], "Should use the translated samples")
+	slot := addItem["slots"].([]interface{})[0].(map[string]interface{})
+	suite.Equal(map[string]interface{}{"enabled": true}, slot["multipleValues"], "Should enable multi-value slots")
+
+	values := languageModel["types"].([]interface{})[0].(map[string]interface{})["values"].([]interface{})
+	suite.Equal(map[string]interface{}{"id": "laundry", "name": map[string]interface{}{"value": "lavar la ropa"}}, values[0],
+		"Should use translated slot type values")
+	suite.Equal(map[string]interface{}{"id": "dishes", "name": map[string]interface{}{"value": "dishes"}}, values[1],
+		"Should fall back to English slot type values")
+
+	dialog := doc["dialog"].(map[string]interface{})
+	dialogIntent := dialog["intents"].([]interface{})[0].(map[string]interface{})
+	suite.Equal(true, dialogIntent["confirmationRequired"], "Should require confirmation for intents w/ a confirmation prompt")
+	dialogSlot := dialogIntent["slots"].([]interface{})[0].(map[string]interface{})
+	suite.Equal(true, dialogSlot["elicitationRequired"], "Should require slots w/ an elicitation prompt")
+	suite.Equal("Elicit.Slot.AddItem.item", dialogSlot["prompts"].(map[string]interface{})["elicitation"],
+		"Should refer to the elicitation prompt by id")
+
+	prompts := doc["prompts"].([]interface{})
+	suite.Equal(map[string]interface{}{
+		"id":         "Confirm.Intent.AddItem",
+		"variations": []interface{}{map[string]interface{}{"type": "SSML", "value": "<speak>Are you sure?</speak>"}},
+	}, prompts[0], "Should include SSML prompts")
+	suite.Equal(map[string]interface{}{
+		"id":         "Elicit.Slot.AddItem.item",
+		"variations": []interface{}{map[string]interface{}{"type": "PlainText", "value": "¿Qué artículo?"}},
+	}, prompts[1], "Should include translated plain text prompts")
+
+	doc = suite.export(model.Model{Intents: []model.Intent{{Name: "AMAZON.StopIntent"}}}, language.AmericanEnglish)
+	suite.Nil(doc["dialog"], "Should not include a dialog when there are no prompts")
+}
+
+func (suite ExportSuite) TestExportPromptVariants() {
+	askForItem := speech.NewTemplate("What item?",
+		speech.WithVariants(language.AmericanEnglish, `Say <break time="1s"/> the item`, "Tom &amp; Jerry?"))
+	m := model.Model{
+		Intents: []model.Intent{{
+			Name:    "AddItem",
+			Samples: model.NewPhrases("add {item}"),
+			Slots:   []model.Slot{{Name: "item", Type: "AMAZON.Food", Elicitation: &askForItem}},
+		}},
+	}
+
+	for i := 0; i < 5; i++ {
+		prompts := suite.export(m, language.AmericanEnglish)["prompts"].([]interface{})
+		suite.Equal(map[string]interface{}{
+			"id": "Elicit.Slot.AddItem.item",
+			"variations": []interface{}{
+				map[string]interface{}{"type": "PlainText", "value": "What item?"},
+				map[string]interface{}{"type": "SSML", "value": `<speak>Say <break time="1s"/> the item</speak>`},
+				map[string]interface{}{"type": "SSML", "value": "<speak>Tom &amp; Jerry?</speak>"},
+			},
+		}, prompts[0], "Should export every variant in order, using SSML for the ones w/ markup")
+	}
+}
+
+func (suite ExportSuite) TestExportFailures() {
+	_, err := model.Model{Intents: []model.Intent{{
+		Name:    "AddItem",
+		Samples: model.NewPhrases("add {item} to my list"),
+	}}}.Export(language.AmericanEnglish)
+	suite.Error(err, "Should fail when samples refer to undeclared slots")
+
+	_, err = model.Model{Intents: []model.Intent{{
+		Name:  "AddItem",
+		Slots: []model.Slot{{Name: "item", Type: "TodoItem"}},
+	}}}.Export(language.AmericanEnglish)
+	suite.Error(err, "Should fail when slots use undeclared custom types")
+}
+
+func (suite ExportSuite) TestMerge() {
+	prompt := speech.NewTemplate("Sure?")
+	intent := model.Intent{Name: "AddItem", Samples: model.NewPhrases("add {item}")}.Merge(model.Intent{
+		Samples:      model.NewPhrases("append {item}"),
+		Slots:        []model.Slot{{Name: "item", Type: "AMAZON.Food"}},
+		Confirmation: &prompt,
+	}).Merge(model.Intent{
+		Slots: []model.Slot{{Name: "item", Type: "AMAZON.Drink"}},
+	})
+
+	suite.Equal("AddItem", intent.Name, "Should keep the original name")
+	suite.Equal([]string{"add {item}", "append {item}"}, intent.Samples.For(language.AmericanEnglish), "Should combine samples")
+	suite.Equal([]model.Slot{{Name: "item", Type: "AMAZON.Drink"}}, intent.Slots, "Should replace slots w/ the same name")
+	suite.NotNil(intent.Confirmation, "Should keep the confirmation prompt")
+}
@@ -0,0 +1,239 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Generate creates the source for a Go file in the given package w/ constants for all of the model's
+// intents, slots, and custom slot types, a struct per intent for use w/ `Slots.Bind()`, and a Handlers
+// interface w/ one method per intent. Renaming an intent in your model then becomes a compile error
+// rather than a "no handler registered" failure in front of your users.
+func Generate(m Model, packageName string) ([]byte, error) {
+	data := generateData{Package: packageName}
+	methods := map[string]string{}
+	slotConsts := map[string]string{}
+	usesTime := false
+
+	// Every constant/type we generate lives in the same package scope, so make sure that two parts
+	// of the model (e.g. the slot types "Todo_Item" and "TodoItem") don't end up w/ the same name.
+	identifiers := map[string]string{
+		"Handlers":         "the Handlers interface",
+		"RegisterHandlers": "the RegisterHandlers function",
+	}
+	declare := func(identifier string, source string) error {
+		if other, ok := identifiers[identifier]; ok {
+			return fmt.Errorf("model: %s and %s both generate the Go name '%s'", other, source, identifier)
+		}
+		identifiers[identifier] = source
+		return nil
+	}
+
+	for _, intent := range m.Intents {
+		base := intentBaseName(intent.Name)
+		if base == "" {
+			return nil, fmt.Errorf("model: unable to generate a Go name for intent '%s'", intent.Name)
+		}
+		if other, ok := methods[base]; ok {
+			return nil, fmt.Errorf("model: intents '%s' and '%s' both generate the Go name '%s'", other, intent.Name, base)
+		}
+		methods[base] = intent.Name
+		if err := declare("Intent"+base, fmt.Sprintf("intent '%s'", intent.Name)); err != nil {
+			return nil, err
+		}
+		if len(intent.Slots) > 0 {
+			if err := declare(base+"Slots", fmt.Sprintf("the slots of intent '%s'", intent.Name)); err != nil {
+				return nil, err
+			}
+		}
+
+		genIntent := generateIntent{Name: intent.Name, Const: "Intent" + base, Method: base}
+		for _, slot := range intent.Slots {
+			field := goName(slot.Name)
+			if field == "" {
+				return nil, fmt.Errorf("model: unable to generate a Go name for slot '%s'", slot.Name)
+			}
+			// Intents can share slots w/ the same name, so only declare the constant the first time.
+			if slotConsts["Slot"+field] != slot.Name {
+				if err := declare("Slot"+field, fmt.Sprintf("slot '%s'", slot.Name)); err != nil {
+					return nil, err
+				}
+				slotConsts["Slot"+field] = slot.Name
+			}
+
+			tag := slot.Name
+			if slot.Elicitation != nil {
+				tag += ",required"
+			}
+			goType := slotGoType(slot)
+			usesTime = usesTime || goType == "time.Duration"
+			genIntent.Slots = append(genIntent.Slots, generateSlot{Field: field, Type: goType, Tag: tag})
+		}
+		data.Intents = append(data.Intents, genIntent)
+	}
+
+	for constName, slotName := range slotConsts {
+		data.Slots = append(data.Slots, generateConst{Name: constName, Value: slotName})
+	}
+	sort.Slice(data.Slots, func(i, j int) bool { return data.Slots[i].Name < data.Slots[j].Name })
+
+	for _, slotType := range m.SlotTypes {
+		name := goName(slotType.Name)
+		if name == "" {
+			return nil, fmt.Errorf("model: unable to generate a Go name for slot type '%s'", slotType.Name)
+		}
+		if err := declare("SlotType"+name, fmt.Sprintf("slot type '%s'", slotType.Name)); err != nil {
+			return nil, err
+		}
+		data.SlotTypes = append(data.SlotTypes, generateConst{Name: "SlotType" + name, Value: slotType.Name})
+	}
+	data.UsesTime = usesTime
+
+	source := bytes.Buffer{}
+	if err := generateTemplate.Execute(&source, data); err != nil {
+		return nil, fmt.Errorf("model: unable to generate code: %v", err)
+	}
+	formatted, err := format.Source(source.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("model: generated invalid code: %v", err)
+	}
+	return formatted, nil
+}
+
+// slotGoType determines the best field type for binding the slot w/ `Slots.Bind()`.
+func slotGoType(slot Slot) string {
+	if slot.Multiple {
+		return "[]string"
+	}
+	switch slot.Type {
+	case "AMAZON.NUMBER", "AMAZON.FOUR_DIGIT_NUMBER":
+		return "int"
+	case "AMAZON.DATE":
+		return "golexa.DateRange"
+	case "AMAZON.TIME":
+		return "golexa.TimeOfDay"
+	case "AMAZON.DURATION":
+		return "time.Duration"
+	default:
+		return "string"
+	}
+}
+
+var patternWordSeparator = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// goName converts names like "item_name" or "AMAZON.CancelIntent" into exported Go identifiers
+// like "ItemName" and "AmazonCancelIntent".
+func goName(name string) string {
+	result := strings.Builder{}
+	for _, word := range patternWordSeparator.Split(name, -1) {
+		if word == "" {
+			continue
+		}
+		if strings.ToUpper(word) == word {
+			word = strings.ToLower(word)
+		}
+		result.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	identifier := result.String()
+	if identifier != "" && identifier[0] >= '0' && identifier[0] <= '9' {
+		identifier = "X" + identifier
+	}
+	return identifier
+}
+
+// intentBaseName is the Go name for the intent w/o the redundant "Intent" suffix, so "PlayIntent"
+// results in the constant "IntentPlay" and the handler method "Play".
+func intentBaseName(intentName string) string {
+	base := goName(intentName)
+	if trimmed := strings.TrimSuffix(base, "Intent"); trimmed != "" {
+		return trimmed
+	}
+	return base
+}
+
+type generateData struct {
+	Package   string
+	Intents   []generateIntent
+	Slots     []generateConst
+	SlotTypes []generateConst
+	UsesTime  bool
+}
+
+type generateIntent struct {
+	Name   string
+	Const  string
+	Method string
+	Slots  []generateSlot
+}
+
+type generateSlot struct {
+	Field string
+	Type  string
+	Tag   string
+}
+
+type generateConst struct {
+	Name  string
+	Value string
+}
+
+var generateTemplate = template.Must(template.New("generate").Parse(`// Code generated by "golexa model generate"; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+	{{if .UsesTime}}"time"{{end}}
+
+	"github.com/robsignorelli/golexa"
+)
+
+// The names of all of the intents in the interaction model.
+const (
+{{- range .Intents}}
+	{{.Const}} = {{printf "%q" .Name}}
+{{- end}}
+)
+{{if .Slots}}
+// The names of all of the slots in the interaction model.
+const (
+{{- range .Slots}}
+	{{.Name}} = {{printf "%q" .Value}}
+{{- end}}
+)
+{{end}}
+{{- if .SlotTypes}}
+// The names of all of the custom slot types in the interaction model.
+const (
+{{- range .SlotTypes}}
+	{{.Name}} = {{printf "%q" .Value}}
+{{- end}}
+)
+{{end}}
+{{- range .Intents}}{{if .Slots}}
+// {{.Method}}Slots contains the slots for the {{printf "%q" .Name}} intent. Use request.BindSlots() to fill it in.
+type {{.Method}}Slots struct {
+{{- range .Slots}}
+	{{.Field}} {{.Type}} ` + "`" + `slot:"{{.Tag}}"` + "`" + `
+{{- end}}
+}
+{{end}}{{end}}
+// Handlers has one method for each of the intents in the interaction model.
+type Handlers interface {
+{{- range .Intents}}
+	{{.Method}}(ctx context.Context, request golexa.Request) (golexa.Response, error)
+{{- end}}
+}
+
+// RegisterHandlers routes each of the intents in the interaction model to its method on your handlers.
+func RegisterHandlers(router golexa.Router, handlers Handlers) {
+{{- range .Intents}}
+	router.RouteIntent({{.Const}}, handlers.{{.Method}})
+{{- end}}
+}
+`))
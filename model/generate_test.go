@@ -0,0 +1,131 @@
+package model_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/robsignorelli/golexa/model"
+	"github.com/robsignorelli/golexa/speech"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/text/language"
+)
+
+func TestGenerateSuite(t *testing.T) {
+	suite.Run(t, new(GenerateSuite))
+}
+
+type GenerateSuite struct {
+	suite.Suite
+}
+
+func (suite GenerateSuite) TestParse() {
+	askForItem := speech.NewTemplate("What item?")
+	original := model.Model{
+		InvocationName: model.NewPhrases("to do list"),
+		Intents: []model.Intent{{
+			Name:    "AddItem",
+			Samples: model.NewPhrases("add {item} to my list"),
+			Slots:   []model.Slot{{Name: "item", Type: "TodoItem", Elicitation: &askForItem, Multiple: true}},
+		}},
+		SlotTypes: []model.SlotType{{Name: "TodoItem", Values: []model.SlotTypeValue{
+			model.NewSlotTypeValue("laundry", "laundry", "wash clothes"),
+		}}},
+	}
+	data, err := original.Export(language.AmericanEnglish)
+	suite.Require().NoError(err, "Should export valid models")
+
+	spanish := language.MustParse("es-MX")
+	m, err := model.Parse(bytes.NewReader(data), spanish)
+	suite.Require().NoError(err, "Should parse exported models")
+	suite.Equal([]string{"to do list"}, m.InvocationName.For(spanish), "Should parse the invocation name")
+
+	suite.Require().Len(m.Intents, 1, "Should parse the intents")
+	suite.Equal([]string{"add {item} to my list"}, m.Intents[0].Samples.For(spanish), "Should parse samples for the given language")
+	suite.Require().Len(m.Intents[0].Slots, 1, "Should parse the slots")
+	slot := m.Intents[0].Slots[0]
+	suite.True(slot.Multiple, "Should parse multi-value slots")
+	suite.Require().NotNil(slot.Elicitation, "Should parse elicitation prompts")
+	prompt, _ := slot.Elicitation.Eval(speech.TemplateContext{Language: spanish})
+	suite.Equal("What item?", prompt, "Should parse elicitation prompts for the given language")
+	suite.Nil(slot.Confirmation, "Should not make up prompts that aren't in the model")
+
+	suite.Equal([]string{"laundry", "wash clothes"}, m.SlotTypes[0].Values[0].Names.For(spanish), "Should parse slot type values")
+
+	_, err = model.Parse(strings.NewReader("{"), spanish)
+	suite.Error(err, "Should fail on malformed JSON")
+}
+
+func (suite GenerateSuite) TestGenerate() {
+	askForItem := speech.NewTemplate("What item?")
+	m := model.Model{
+		Intents: []model.Intent{
+			{Name: "AMAZON.StopIntent"},
+			{Name: "PlayIntent", Slots: []model.Slot{
+				{Name: "media_type", Type: "Media", Elicitation: &askForItem},
+				{Name: "count", Type: "AMAZON.NUMBER"},
+				{Name: "length", Type: "AMAZON.DURATION"},
+				{Name: "when", Type: "AMAZON.DATE"},
+				{Name: "songs", Type: "AMAZON.MusicRecording", Multiple: true},
+			}},
+		},
+		SlotTypes: []model.SlotType{{Name: "Media"}},
+	}
+
+	source, err := model.Generate(m, "player")
+	suite.Require().NoError(err, "Should generate valid code")
+	code := string(source)
+
+	for _, expected := range []string{
+		"package player",
+		`"time"`,
+		`IntentAmazonStop = "AMAZON.StopIntent"`,
+		`IntentPlay       = "PlayIntent"`,
+		`SlotMediaType = "media_type"`,
+		`SlotTypeMedia = "Media"`,
+		"type PlaySlots struct {",
+		"MediaType string           `slot:\"media_type,required\"`",
+		"Count     int              `slot:\"count\"`",
+		"Length    time.Duration    `slot:\"length\"`",
+		"When      golexa.DateRange `slot:\"when\"`",
+		"Songs     []string         `slot:\"songs\"`",
+		"Play(ctx context.Context, request golexa.Request) (golexa.Response, error)",
+		"router.RouteIntent(IntentAmazonStop, handlers.AmazonStop)",
+	} {
+		suite.Contains(code, expected, "Should generate: "+expected)
+	}
+	suite.NotContains(code, "AmazonStopSlots", "Should not generate slot structs for intents w/o slots")
+
+	_, err = model.Generate(model.Model{Intents: []model.Intent{{Name: "Play"}, {Name: "PlayIntent"}}}, "player")
+	suite.Error(err, "Should fail when intents result in the same Go name")
+
+	_, err = model.Generate(model.Model{Intents: []model.Intent{
+		{Name: "Play", Slots: []model.Slot{{Name: "media_type", Type: "Media"}}},
+		{Name: "Stop", Slots: []model.Slot{{Name: "mediaType", Type: "Media"}}},
+	}}, "player")
+	suite.Error(err, "Should fail when slots result in the same Go name")
+
+	_, err = model.Generate(model.Model{SlotTypes: []model.SlotType{{Name: "Todo_Item"}, {Name: "TodoItem"}}}, "todo")
+	suite.Error(err, "Should fail when slot types result in the same Go name")
+
+	_, err = model.Generate(model.Model{
+		Intents:   []model.Intent{{Name: "Add", Slots: []model.Slot{{Name: "type_item", Type: "AMAZON.Food"}}}},
+		SlotTypes: []model.SlotType{{Name: "Item"}},
+	}, "todo")
+	suite.Error(err, "Should fail when a slot and slot type result in the same Go name")
+
+	_, err = model.Generate(model.Model{Intents: []model.Intent{
+		{Name: "Slot", Slots: []model.Slot{{Name: "slots", Type: "AMAZON.Food"}}},
+	}}, "player")
+	suite.Error(err, "Should fail when an intent's slot struct and a slot result in the same Go name")
+
+	_, err = model.Generate(model.Model{Intents: []model.Intent{
+		{Name: "Play", Slots: []model.Slot{{Name: "media_type", Type: "Media"}}},
+		{Name: "Stop", Slots: []model.Slot{{Name: "media_type", Type: "Media"}}},
+	}}, "player")
+	suite.NoError(err, "Should allow intents to share slots")
+
+	source, err = model.Generate(model.Model{Intents: []model.Intent{{Name: "Stop"}}}, "player")
+	suite.Require().NoError(err, "Should generate code for models w/o slots")
+	suite.NotContains(string(source), `"time"`, "Should only import time when necessary")
+}
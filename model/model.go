@@ -0,0 +1,148 @@
+// Package model lets you declare your skill's interaction model (sample utterances, slots, custom slot
+// types, and dialog prompts) in Go right next to the code that handles it. You can then export it as
+// the per-locale JSON that the ASK CLI and SMAPI expect so your code and your model never drift apart.
+package model
+
+import (
+	"strings"
+
+	"github.com/robsignorelli/golexa/speech"
+	"golang.org/x/text/language"
+)
+
+// Phrases are the things a user might say (or what your skill is called) w/ translations for
+// other languages. Just like speech templates, you define the English (US) phrases up front and
+// use `WithTranslation()` to localize them.
+type Phrases struct {
+	translations map[language.Tag][]string
+}
+
+// NewPhrases creates a set of phrases whose English (US) translation is the given text.
+func NewPhrases(englishPhrases ...string) Phrases {
+	return Phrases{}.WithTranslation(language.AmericanEnglish, englishPhrases...)
+}
+
+// WithTranslation returns a copy of the phrases that includes versions localized for the given language.
+func (p Phrases) WithTranslation(lang language.Tag, localizedPhrases ...string) Phrases {
+	translations := map[language.Tag][]string{}
+	for tag, phrases := range p.translations {
+		translations[tag] = phrases
+	}
+	translations[lang] = append(append([]string{}, translations[lang]...), localizedPhrases...)
+	return Phrases{translations: translations}
+}
+
+// For returns the phrases for the given language. Just like speech templates, we fall back to
+// more general versions of the language (e.g. "es-MX" to "es") and ultimately English (US).
+func (p Phrases) For(lang language.Tag) []string {
+	if phrases, ok := p.translations[lang]; ok {
+		return phrases
+	}
+	if lang.IsRoot() {
+		return p.translations[language.AmericanEnglish]
+	}
+	return p.For(lang.Parent())
+}
+
+// merge combines both sets of phrases, language by language.
+func (p Phrases) merge(other Phrases) Phrases {
+	merged := p
+	for lang, phrases := range other.translations {
+		merged = merged.WithTranslation(lang, phrases...)
+	}
+	return merged
+}
+
+// Model is the entire interaction model for your skill. You typically don't build this yourself; you
+// declare the pieces alongside your routes and `Skill.Model()` assembles it for you.
+type Model struct {
+	// InvocationName is what the user says to open your skill (e.g. "Alexa, open to do list").
+	InvocationName Phrases
+	Intents        []Intent
+	SlotTypes      []SlotType
+}
+
+// Intent describes how users can invoke one of your intents.
+type Intent struct {
+	Name string
+	// Samples are the utterances that trigger this intent. Refer to slots using "{slot_name}".
+	Samples Phrases
+	Slots   []Slot
+	// Confirmation, if set, has Alexa confirm the whole intent (e.g. "Are you sure you want to
+	// delete everything?") before sending it to you once all required slots are filled.
+	Confirmation *speech.Template
+}
+
+// Slot describes one of the placeholders in your intent's sample utterances.
+type Slot struct {
+	Name string
+	// Type is either one of Amazon's built-in types (e.g. "AMAZON.NUMBER") or one of your SlotTypes.
+	Type string
+	// Samples are what users might say when Alexa prompts them for just this slot.
+	Samples Phrases
+	// Multiple indicates that users can say several values at once (e.g. "eggs, milk, and bread").
+	Multiple bool
+	// Elicitation, if set, makes this slot required and is what Alexa says to ask for it.
+	Elicitation *speech.Template
+	// Confirmation, if set, is what Alexa says to confirm the slot's value w/ the user.
+	Confirmation *speech.Template
+}
+
+// SlotType is one of your custom slot types and all of the values it supports.
+type SlotType struct {
+	Name   string
+	Values []SlotTypeValue
+}
+
+// SlotTypeValue is a single value for a custom slot type. The first phrase for each language is the
+// value itself and any others are its synonyms.
+type SlotTypeValue struct {
+	ID    string
+	Names Phrases
+}
+
+// NewSlotTypeValue creates an English (US) value for a custom slot type along w/ any synonyms.
+func NewSlotTypeValue(id string, value string, synonyms ...string) SlotTypeValue {
+	return SlotTypeValue{ID: id, Names: NewPhrases(append([]string{value}, synonyms...)...)}
+}
+
+// WithTranslation returns a copy of the value that includes a version localized for the given language.
+func (v SlotTypeValue) WithTranslation(lang language.Tag, value string, synonyms ...string) SlotTypeValue {
+	v.Names = v.Names.WithTranslation(lang, append([]string{value}, synonyms...)...)
+	return v
+}
+
+// Merge adds the samples and slots from 'other' to the intent, which is how multiple routes
+// for the same intent can each contribute a piece of its definition. Slots w/ the same name
+// are replaced rather than duplicated.
+func (intent Intent) Merge(other Intent) Intent {
+	intent.Samples = intent.Samples.merge(other.Samples)
+	if other.Confirmation != nil {
+		intent.Confirmation = other.Confirmation
+	}
+
+	slots := append([]Slot{}, intent.Slots...)
+	for _, slot := range other.Slots {
+		if i := indexOfSlot(slots, slot.Name); i >= 0 {
+			slots[i] = slot
+		} else {
+			slots = append(slots, slot)
+		}
+	}
+	intent.Slots = slots
+	return intent
+}
+
+func indexOfSlot(slots []Slot, name string) int {
+	for i, slot := range slots {
+		if slot.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// isBuiltInType returns true for Amazon's own slot types, which you don't need to declare.
+func isBuiltInType(slotType string) bool {
+	return strings.HasPrefix(slotType, "AMAZON.")
+}
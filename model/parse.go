@@ -0,0 +1,104 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/robsignorelli/golexa/speech"
+	"golang.org/x/text/language"
+)
+
+// Parse reads an interaction model JSON file (e.g. "models/en-US.json" from an ASK CLI project) for the
+// given language. This is the reverse of `Export()`, so all of the samples, slot type values, and
+// prompts are assigned to that language.
+func Parse(r io.Reader, lang language.Tag) (Model, error) {
+	doc := jsonFile{}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return Model{}, fmt.Errorf("model: unable to parse interaction model: %v", err)
+	}
+
+	prompts := map[string]string{}
+	for _, prompt := range doc.InteractionModel.Prompts {
+		if len(prompt.Variations) > 0 {
+			prompts[prompt.ID] = prompt.Variations[0].Value
+		}
+	}
+
+	dialogIntents := map[string]jsonDialogIntent{}
+	if doc.InteractionModel.Dialog != nil {
+		for _, dialogIntent := range doc.InteractionModel.Dialog.Intents {
+			dialogIntents[dialogIntent.Name] = dialogIntent
+		}
+	}
+
+	languageModel := doc.InteractionModel.LanguageModel
+	m := Model{InvocationName: Phrases{}.WithTranslation(lang, languageModel.InvocationName)}
+	for _, intent := range languageModel.Intents {
+		parsed, err := parseIntent(intent, dialogIntents[intent.Name], prompts, lang)
+		if err != nil {
+			return Model{}, err
+		}
+		m.Intents = append(m.Intents, parsed)
+	}
+	for _, slotType := range languageModel.Types {
+		parsed := SlotType{Name: slotType.Name}
+		for _, value := range slotType.Values {
+			names := append([]string{value.Name.Value}, value.Name.Synonyms...)
+			parsed.Values = append(parsed.Values, SlotTypeValue{ID: value.ID, Names: Phrases{}.WithTranslation(lang, names...)})
+		}
+		m.SlotTypes = append(m.SlotTypes, parsed)
+	}
+	return m, nil
+}
+
+func parseIntent(intent jsonIntent, dialogIntent jsonDialogIntent, prompts map[string]string, lang language.Tag) (Intent, error) {
+	var err error
+	parsed := Intent{
+		Name:    intent.Name,
+		Samples: Phrases{}.WithTranslation(lang, intent.Samples...),
+	}
+	if parsed.Confirmation, err = parsePrompt(dialogIntent.Prompts["confirmation"], prompts, lang); err != nil {
+		return Intent{}, err
+	}
+
+	for _, slot := range intent.Slots {
+		s := Slot{
+			Name:     slot.Name,
+			Type:     slot.Type,
+			Samples:  Phrases{}.WithTranslation(lang, slot.Samples...),
+			Multiple: slot.MultipleValues != nil && slot.MultipleValues.Enabled,
+		}
+		for _, dialogSlot := range dialogIntent.Slots {
+			if dialogSlot.Name != slot.Name {
+				continue
+			}
+			if s.Elicitation, err = parsePrompt(dialogSlot.Prompts["elicitation"], prompts, lang); err != nil {
+				return Intent{}, err
+			}
+			if s.Confirmation, err = parsePrompt(dialogSlot.Prompts["confirmation"], prompts, lang); err != nil {
+				return Intent{}, err
+			}
+		}
+		parsed.Slots = append(parsed.Slots, s)
+	}
+	return parsed, nil
+}
+
+// parsePrompt turns the prompt w/ the given id into a speech template. You get nil if there's
+// no such prompt, which is how the model indicates that the slot/intent doesn't need it.
+func parsePrompt(id string, prompts map[string]string, lang language.Tag) (prompt *speech.Template, err error) {
+	text, ok := prompts[id]
+	if id == "" || !ok {
+		return nil, nil
+	}
+
+	// NewTemplate panics on bad templates, but a weird prompt in someone's JSON file shouldn't crash the tool.
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			prompt, err = nil, fmt.Errorf("model: prompt '%s': %v", id, recovered)
+		}
+	}()
+	template := speech.NewTemplate(text, speech.WithTranslation(lang, text))
+	return &template, nil
+}
@@ -0,0 +1,170 @@
+package proactive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/robsignorelli/golexa/lwa"
+	"golang.org/x/oauth2"
+)
+
+// The base URLs for the Alexa APIs in each region. Send your events to the region
+// where your skill's users are located.
+const (
+	NorthAmerica = "https://api.amazonalexa.com"
+	Europe       = "https://api.eu.amazonalexa.com"
+	FarEast      = "https://api.fe.amazonalexa.com"
+)
+
+// DefaultTokenURL is the Login with Amazon endpoint used to exchange your skill's client
+// id/secret for an access token that can send proactive events.
+const DefaultTokenURL = lwa.DefaultTokenURL
+
+// Stage determines which version of your skill receives the events you send.
+type Stage string
+
+const (
+	// StageLive sends events to users of the live/certified version of your skill.
+	StageLive = Stage("live")
+	// StageDevelopment sends events to users of the development version of your skill.
+	StageDevelopment = Stage("development")
+)
+
+// NewClient creates a client for the Proactive Events API that lets you send notifications to your
+// users outside of a skill session. The client id and secret are the "Alexa Skill Messaging" credentials
+// in the "Permissions" section of your skill in the developer console. By default, events are sent
+// to the live stage of your skill in North America.
+//
+// See: https://developer.amazon.com/en-US/docs/alexa/smapi/proactive-events-api.html
+func NewClient(clientID, clientSecret string, options ...ClientOption) *Client {
+	client := Client{
+		apiURL:     NorthAmerica,
+		tokenURL:   DefaultTokenURL,
+		stage:      StageLive,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+	for _, opt := range options {
+		opt(&client)
+	}
+	if client.tokens == nil {
+		credentials := lwa.NewCredentials(clientID, clientSecret,
+			lwa.WithTokenURL(client.tokenURL),
+			lwa.WithHTTPClient(client.httpClient))
+		client.tokens = credentials.TokenSource(lwa.ScopeProactiveEvents)
+	}
+	return &client
+}
+
+// NewTokenSourceClient creates a client that gets its access tokens from the given source rather than
+// fetching its own, so you don't need to pass along a client id/secret. This is the same as calling
+// `NewClient()` w/ blank credentials and `WithTokenSource()`:
+//
+//	credentials := lwa.NewCredentials(clientID, clientSecret)
+//	client := proactive.NewTokenSourceClient(credentials.TokenSource(lwa.ScopeProactiveEvents))
+func NewTokenSourceClient(tokens oauth2.TokenSource, options ...ClientOption) *Client {
+	return NewClient("", "", append([]ClientOption{WithTokenSource(tokens)}, options...)...)
+}
+
+// ClientOption tweaks the settings of your proactive events client. Please use the built-in
+// helpers like WithAPIURL() and WithStage().
+type ClientOption func(*Client)
+
+// WithAPIURL changes the base URL of the Alexa API that events are sent to. Use one of the region
+// constants such as proactive.Europe or the address of a local mock server for testing.
+func WithAPIURL(apiURL string) ClientOption {
+	return func(client *Client) {
+		client.apiURL = strings.TrimSuffix(apiURL, "/")
+	}
+}
+
+// WithTokenURL changes the Login with Amazon endpoint used to obtain access tokens. This is
+// really only useful for pointing the client at a local mock server for testing.
+func WithTokenURL(tokenURL string) ClientOption {
+	return func(client *Client) {
+		client.tokenURL = tokenURL
+	}
+}
+
+// WithStage determines whether events are sent to the live or development version of your skill.
+func WithStage(stage Stage) ClientOption {
+	return func(client *Client) {
+		client.stage = stage
+	}
+}
+
+// WithTokenSource supplies the source of access tokens rather than having the client fetch its own
+// using the client id/secret, which are ignored (and can be blank) when you use this. This lets you
+// share one set of cached tokens across all of your API clients (also see `NewTokenSourceClient()`).
+func WithTokenSource(tokens oauth2.TokenSource) ClientOption {
+	return func(client *Client) {
+		client.tokens = tokens
+	}
+}
+
+// WithHTTPClient overrides the HTTP client used to communicate with Amazon (e.g. to change timeouts).
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(client *Client) {
+		client.httpClient = httpClient
+	}
+}
+
+// Client sends proactive events to the Alexa API on behalf of your skill.
+type Client struct {
+	apiURL     string
+	tokenURL   string
+	stage      Stage
+	httpClient *http.Client
+	tokens     oauth2.TokenSource
+}
+
+// Send publishes the event so that Alexa can notify the event's audience. The event must have
+// an audience (see Event.Unicast() and Event.Multicast()) and localized attributes for every
+// locale your skill supports if its payload refers to any.
+func (client *Client) Send(ctx context.Context, event Event) error {
+	if event.audience == nil {
+		return fmt.Errorf("proactive: event '%s' has no audience", event.name)
+	}
+
+	token, err := lwa.AccessToken(ctx, client.tokens)
+	if err != nil {
+		return fmt.Errorf("proactive: %v", err)
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("proactive: encode event: %v", err)
+	}
+
+	request, err := http.NewRequest(http.MethodPost, client.eventsURL(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("proactive: send event: %v", err)
+	}
+	request = request.WithContext(ctx)
+	request.Header.Set("Authorization", "Bearer "+token)
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := client.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("proactive: send event: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		message, _ := ioutil.ReadAll(response.Body)
+		return fmt.Errorf("proactive: send event: %s: %s", response.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+func (client *Client) eventsURL() string {
+	if client.stage == StageDevelopment {
+		return client.apiURL + "/v1/proactiveEvents/stages/development"
+	}
+	return client.apiURL + "/v1/proactiveEvents"
+}
@@ -0,0 +1,166 @@
+package proactive_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/robsignorelli/golexa/proactive"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/oauth2"
+)
+
+func TestClientSuite(t *testing.T) {
+	suite.Run(t, new(ClientSuite))
+}
+
+type ClientSuite struct {
+	suite.Suite
+}
+
+// mockAPI fakes both Login with Amazon and the Alexa API, capturing what the client sent.
+type mockAPI struct {
+	server      *httptest.Server
+	tokenForm   map[string]string
+	eventPath   string
+	eventAuth   string
+	eventJSON   map[string]interface{}
+	eventStatus int
+}
+
+func newMockAPI() *mockAPI {
+	api := &mockAPI{eventStatus: http.StatusAccepted}
+	api.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth/o2/token" {
+			_ = r.ParseForm()
+			api.tokenForm = map[string]string{}
+			for key := range r.PostForm {
+				api.tokenForm[key] = r.PostForm.Get(key)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token":"token.123","expires_in":3600,"token_type":"bearer"}`))
+			return
+		}
+
+		api.eventPath = r.URL.Path
+		api.eventAuth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&api.eventJSON)
+		w.WriteHeader(api.eventStatus)
+	}))
+	return api
+}
+
+func (api *mockAPI) client(options ...proactive.ClientOption) *proactive.Client {
+	options = append([]proactive.ClientOption{
+		proactive.WithAPIURL(api.server.URL),
+		proactive.WithTokenURL(api.server.URL + "/auth/o2/token"),
+	}, options...)
+	return proactive.NewClient("client.id", "client.secret", options...)
+}
+
+func (suite ClientSuite) TestSend_Unicast() {
+	api := newMockAPI()
+	defer api.server.Close()
+
+	event := proactive.NewEvent(proactive.OrderStatus{
+		SellerName: proactive.LocalizedAttribute("sellerName"),
+		Status:     "ORDER_SHIPPED",
+	})
+	event = event.
+		Unicast("user.123").
+		ReferenceID("order.456").
+		Localized("en-US", map[string]string{"sellerName": "Speedy Shoes"}).
+		Localized("es-MX", map[string]string{"sellerName": "Zapatos Rápidos"})
+
+	err := api.client().Send(context.TODO(), event)
+	suite.Require().NoError(err,
+		"Should send events successfully when the API accepts them")
+
+	suite.Equal("client_credentials", api.tokenForm["grant_type"],
+		"Should use the client credentials grant to get a token")
+	suite.Equal("client.id", api.tokenForm["client_id"],
+		"Should send the client id to get a token")
+	suite.Equal("client.secret", api.tokenForm["client_secret"],
+		"Should send the client secret to get a token")
+	suite.Equal("alexa::proactive_events", api.tokenForm["scope"],
+		"Should request the proactive events scope")
+
+	suite.Equal("/v1/proactiveEvents", api.eventPath,
+		"Should send events to the live stage by default")
+	suite.Equal("Bearer token.123", api.eventAuth,
+		"Should authorize the request using the LWA token")
+
+	suite.Equal("order.456", api.eventJSON["referenceId"],
+		"Should include the reference id")
+	suite.Equal(map[string]interface{}{"type": "Unicast", "payload": map[string]interface{}{"user": "user.123"}},
+		api.eventJSON["relevantAudience"],
+		"Should target the single user")
+	suite.Equal(map[string]interface{}{
+		"name": "AMAZON.OrderStatus.Updated",
+		"payload": map[string]interface{}{
+			"state": map[string]interface{}{"status": "ORDER_SHIPPED"},
+			"order": map[string]interface{}{"seller": map[string]interface{}{"name": "localizedattribute:sellerName"}},
+		},
+	}, api.eventJSON["event"],
+		"Should encode the schema name and payload")
+	suite.Equal([]interface{}{
+		map[string]interface{}{"locale": "en-US", "sellerName": "Speedy Shoes"},
+		map[string]interface{}{"locale": "es-MX", "sellerName": "Zapatos Rápidos"},
+	}, api.eventJSON["localizedAttributes"],
+		"Should include localized attributes for every locale")
+}
+
+func (suite ClientSuite) TestSend_Multicast() {
+	api := newMockAPI()
+	defer api.server.Close()
+
+	event := proactive.NewEvent(proactive.MessageAlert{CreatorName: "Andy", Count: 3}).Multicast()
+
+	err := api.client(proactive.WithStage(proactive.StageDevelopment)).Send(context.TODO(), event)
+	suite.Require().NoError(err,
+		"Should send events successfully when the API accepts them")
+
+	suite.Equal("/v1/proactiveEvents/stages/development", api.eventPath,
+		"Should send events to the development stage when asked to")
+	suite.Equal(map[string]interface{}{"type": "Multicast", "payload": map[string]interface{}{}},
+		api.eventJSON["relevantAudience"],
+		"Should target all subscribed users")
+	suite.Equal(map[string]interface{}{
+		"name": "AMAZON.MessageAlert.Activated",
+		"payload": map[string]interface{}{
+			"state":        map[string]interface{}{"status": "UNREAD", "freshness": "NEW"},
+			"messageGroup": map[string]interface{}{"creator": map[string]interface{}{"name": "Andy"}, "count": float64(3)},
+		},
+	}, api.eventJSON["event"],
+		"Should encode the schema name and payload w/ default values")
+}
+
+func (suite ClientSuite) TestSend_Failure() {
+	api := newMockAPI()
+	defer api.server.Close()
+
+	event := proactive.NewEvent(proactive.MessageAlert{CreatorName: "Andy", Count: 3})
+	suite.Error(api.client().Send(context.TODO(), event),
+		"Should fail when the event has no audience")
+
+	api.eventStatus = http.StatusForbidden
+	suite.Error(api.client().Send(context.TODO(), event.Multicast()),
+		"Should fail when the API rejects the event")
+}
+
+func (suite ClientSuite) TestTokenSourceClient() {
+	api := newMockAPI()
+	defer api.server.Close()
+
+	client := proactive.NewTokenSourceClient(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "shared.123"}),
+		proactive.WithAPIURL(api.server.URL))
+	event := proactive.NewEvent(proactive.MessageAlert{CreatorName: "Andy", Count: 3}).Multicast()
+	suite.Require().NoError(client.Send(context.TODO(), event),
+		"Should send events w/o a client id/secret")
+	suite.Nil(api.tokenForm,
+		"Should not fetch its own token")
+	suite.Equal("Bearer shared.123", api.eventAuth,
+		"Should authorize the request using the shared token source")
+}
@@ -0,0 +1,130 @@
+package proactive
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// NewEvent creates an event for one of the standard Alexa schemas (e.g. MessageAlert or OrderStatus)
+// that you can publish using Client.Send(). By default the event expires in one hour and has
+// a randomly generated reference id. You must still specify the audience using Unicast() or Multicast().
+func NewEvent(payload Payload) Event {
+	return Event{
+		name:        payload.EventName(),
+		payload:     payload,
+		referenceID: randomReferenceID(),
+		timestamp:   time.Now(),
+		expiryTime:  time.Now().Add(time.Hour),
+	}
+}
+
+// Payload is the schema-specific data for an event. Use one of the standard schemas like MessageAlert
+// or OrderStatus, or implement this yourself for any schema that we don't support out of the box.
+type Payload interface {
+	EventName() string
+}
+
+// Event is a single notification that you want to send to one or more users. It is a builder, so
+// each of its functions returns a modified copy of the event rather than mutating the original.
+type Event struct {
+	name                string
+	payload             Payload
+	referenceID         string
+	timestamp           time.Time
+	expiryTime          time.Time
+	localizedAttributes []map[string]string
+	audience            *audience
+}
+
+// Unicast indicates that this event should only be sent to a single user of your skill. The
+// user id is the same value as `Request.UserID()` when they interact w/ your skill.
+func (e Event) Unicast(userID string) Event {
+	e.audience = &audience{
+		Type:    "Unicast",
+		Payload: map[string]string{"user": userID},
+	}
+	return e
+}
+
+// Multicast indicates that this event should be sent to all users that have subscribed to this
+// type of event for your skill.
+func (e Event) Multicast() Event {
+	e.audience = &audience{
+		Type:    "Multicast",
+		Payload: map[string]string{},
+	}
+	return e
+}
+
+// Localized supplies the values for any localized attributes that your payload refers to
+// (see LocalizedAttribute()) for one of the locales your skill supports (e.g. "en-US").
+func (e Event) Localized(locale string, attributes map[string]string) Event {
+	localized := map[string]string{"locale": locale}
+	for key, value := range attributes {
+		localized[key] = value
+	}
+	e.localizedAttributes = append(e.localizedAttributes[:len(e.localizedAttributes):len(e.localizedAttributes)], localized)
+	return e
+}
+
+// ReferenceID overrides the randomly generated id for this event. Alexa uses this to de-duplicate
+// events, so sending another event with the same id will update the original notification.
+func (e Event) ReferenceID(referenceID string) Event {
+	e.referenceID = referenceID
+	return e
+}
+
+// ExpiresIn determines how long the notification stays available to the user. Alexa requires
+// this to be between 5 minutes and 24 hours.
+func (e Event) ExpiresIn(duration time.Duration) Event {
+	e.expiryTime = e.timestamp.Add(duration)
+	return e
+}
+
+// MarshalJSON encodes the event in the format expected by the Proactive Events API.
+func (e Event) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Timestamp           string              `json:"timestamp"`
+		ReferenceID         string              `json:"referenceId"`
+		ExpiryTime          string              `json:"expiryTime"`
+		Event               eventBody           `json:"event"`
+		LocalizedAttributes []map[string]string `json:"localizedAttributes,omitempty"`
+		RelevantAudience    *audience           `json:"relevantAudience"`
+	}{
+		Timestamp:           formatTime(e.timestamp),
+		ReferenceID:         e.referenceID,
+		ExpiryTime:          formatTime(e.expiryTime),
+		Event:               eventBody{Name: e.name, Payload: e.payload},
+		LocalizedAttributes: e.localizedAttributes,
+		RelevantAudience:    e.audience,
+	})
+}
+
+type eventBody struct {
+	Name    string  `json:"name"`
+	Payload Payload `json:"payload"`
+}
+
+type audience struct {
+	Type    string            `json:"type"`
+	Payload map[string]string `json:"payload"`
+}
+
+// LocalizedAttribute creates a reference to one of the values you supply in Event.Localized(), so
+// Alexa can use the appropriate text for each user's locale. For instance you can set the seller
+// name of an OrderStatus to `LocalizedAttribute("sellerName")`.
+func LocalizedAttribute(key string) string {
+	return "localizedattribute:" + key
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+func randomReferenceID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,215 @@
+package proactive
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// MessageAlert lets the user know that they have new messages (AMAZON.MessageAlert.Activated). For
+// instance, "You have 3 new messages from Andy".
+//
+// See: https://developer.amazon.com/en-US/docs/alexa/smapi/schemas-for-proactive-events.html#message-alert
+type MessageAlert struct {
+	// CreatorName is the sender of the messages.
+	CreatorName string
+	// Count is the number of new messages.
+	Count int
+	// Status is either "UNREAD" or "FLAGGED". Defaults to "UNREAD".
+	Status string
+	// Freshness is either "NEW" or "OVERDUE". Defaults to "NEW".
+	Freshness string
+	// Urgency is either "URGENT" or blank.
+	Urgency string
+}
+
+// EventName returns the name of the schema for this payload.
+func (m MessageAlert) EventName() string {
+	return "AMAZON.MessageAlert.Activated"
+}
+
+// MarshalJSON encodes the payload in the format expected by the Proactive Events API.
+func (m MessageAlert) MarshalJSON() ([]byte, error) {
+	type creator struct {
+		Name string `json:"name"`
+	}
+	type state struct {
+		Status    string `json:"status"`
+		Freshness string `json:"freshness"`
+	}
+	type messageGroup struct {
+		Creator creator `json:"creator"`
+		Count   int     `json:"count"`
+		Urgency string  `json:"urgency,omitempty"`
+	}
+	return json.Marshal(struct {
+		State        state        `json:"state"`
+		MessageGroup messageGroup `json:"messageGroup"`
+	}{
+		State: state{
+			Status:    valueOr(m.Status, "UNREAD"),
+			Freshness: valueOr(m.Freshness, "NEW"),
+		},
+		MessageGroup: messageGroup{
+			Creator: creator{Name: m.CreatorName},
+			Count:   m.Count,
+			Urgency: m.Urgency,
+		},
+	})
+}
+
+// OrderStatus lets the user know that the status of an order they placed has changed
+// (AMAZON.OrderStatus.Updated). For instance, "Your order from Speedy Shoes has shipped".
+//
+// See: https://developer.amazon.com/en-US/docs/alexa/smapi/schemas-for-proactive-events.html#order-status
+type OrderStatus struct {
+	// SellerName is the store the order was placed with. This is typically a
+	// LocalizedAttribute() such as `LocalizedAttribute("sellerName")`.
+	SellerName string
+	// Status is one of "PREORDER_RECEIVED", "ORDER_RECEIVED", "ORDER_PREPARING", "ORDER_SHIPPED",
+	// "ORDER_OUT_FOR_DELIVERY", "ORDER_OUT_FOR_PICKUP", "ORDER_DELIVERED" or "ORDER_PICKED_UP".
+	Status string
+	// ExpectedArrival is optional and only used for shipped/out for delivery orders.
+	ExpectedArrival time.Time
+}
+
+// EventName returns the name of the schema for this payload.
+func (o OrderStatus) EventName() string {
+	return "AMAZON.OrderStatus.Updated"
+}
+
+// MarshalJSON encodes the payload in the format expected by the Proactive Events API.
+func (o OrderStatus) MarshalJSON() ([]byte, error) {
+	type deliveryDetails struct {
+		ExpectedArrival string `json:"expectedArrival"`
+	}
+	type state struct {
+		Status          string           `json:"status"`
+		DeliveryDetails *deliveryDetails `json:"deliveryDetails,omitempty"`
+	}
+	type seller struct {
+		Name string `json:"name"`
+	}
+	type order struct {
+		Seller seller `json:"seller"`
+	}
+
+	payload := struct {
+		State state `json:"state"`
+		Order order `json:"order"`
+	}{
+		State: state{Status: o.Status},
+		Order: order{Seller: seller{Name: o.SellerName}},
+	}
+	if !o.ExpectedArrival.IsZero() {
+		payload.State.DeliveryDetails = &deliveryDetails{ExpectedArrival: formatTime(o.ExpectedArrival)}
+	}
+	return json.Marshal(payload)
+}
+
+// WeatherAlert lets the user know about severe weather in their area (AMAZON.WeatherAlert.Activated).
+//
+// See: https://developer.amazon.com/en-US/docs/alexa/smapi/schemas-for-proactive-events.html#weather-alert
+type WeatherAlert struct {
+	// Source is the provider of the alert. This is typically a LocalizedAttribute() such
+	// as `LocalizedAttribute("source")`.
+	Source string
+	// AlertType is one of "DEFAULT", "TORNADO", "HURRICANE", "SNOW_STORM", "THUNDER_STORM", etc.
+	AlertType string
+}
+
+// EventName returns the name of the schema for this payload.
+func (w WeatherAlert) EventName() string {
+	return "AMAZON.WeatherAlert.Activated"
+}
+
+// MarshalJSON encodes the payload in the format expected by the Proactive Events API.
+func (w WeatherAlert) MarshalJSON() ([]byte, error) {
+	type weatherAlert struct {
+		Source    string `json:"source"`
+		AlertType string `json:"alertType"`
+	}
+	return json.Marshal(struct {
+		WeatherAlert weatherAlert `json:"weatherAlert"`
+	}{
+		WeatherAlert: weatherAlert{
+			Source:    w.Source,
+			AlertType: valueOr(w.AlertType, "DEFAULT"),
+		},
+	})
+}
+
+// MediaContent lets the user know that a book, episode, album, etc. is now available
+// (AMAZON.MediaContent.Available).
+//
+// See: https://developer.amazon.com/en-US/docs/alexa/smapi/schemas-for-proactive-events.html#media-content
+type MediaContent struct {
+	// ContentName is the title of the content. This is typically a LocalizedAttribute().
+	ContentName string
+	// ContentType is one of "BOOK", "EPISODE", "ALBUM", "SINGLE", "MOVIE" or "GAME".
+	ContentType string
+	// ProviderName is who is making the content available. This is typically a LocalizedAttribute().
+	ProviderName string
+	// Method is one of "STREAM", "AIR", "RELEASE", "PREMIERE" or "DROP".
+	Method string
+	// StartTime is when the content becomes available.
+	StartTime time.Time
+}
+
+// EventName returns the name of the schema for this payload.
+func (m MediaContent) EventName() string {
+	return "AMAZON.MediaContent.Available"
+}
+
+// MarshalJSON encodes the payload in the format expected by the Proactive Events API.
+func (m MediaContent) MarshalJSON() ([]byte, error) {
+	type provider struct {
+		Name string `json:"name"`
+	}
+	type availability struct {
+		StartTime string   `json:"startTime"`
+		Provider  provider `json:"provider"`
+		Method    string   `json:"method"`
+	}
+	type content struct {
+		Name        string `json:"name"`
+		ContentType string `json:"contentType"`
+	}
+	return json.Marshal(struct {
+		Availability availability `json:"availability"`
+		Content      content      `json:"content"`
+	}{
+		Availability: availability{
+			StartTime: formatTime(m.StartTime),
+			Provider:  provider{Name: m.ProviderName},
+			Method:    m.Method,
+		},
+		Content: content{
+			Name:        m.ContentName,
+			ContentType: m.ContentType,
+		},
+	})
+}
+
+// CustomPayload lets you send events for any schema that doesn't have a dedicated type. The
+// payload value should marshal to the JSON that the schema expects.
+type CustomPayload struct {
+	Name    string
+	Payload interface{}
+}
+
+// EventName returns the name of the schema for this payload.
+func (c CustomPayload) EventName() string {
+	return c.Name
+}
+
+// MarshalJSON encodes the raw payload value.
+func (c CustomPayload) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Payload)
+}
+
+func valueOr(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
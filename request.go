@@ -1,11 +1,32 @@
 package golexa
 
-import "golang.org/x/text/language"
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/robsignorelli/golexa/speech"
+	"golang.org/x/text/language"
+)
 
 const (
 	RequestTypeCanFulfillIntent = "CanFulfillIntentRequest"
 	RequestTypeIntent           = "IntentRequest"
 	RequestTypeLaunch           = "LaunchRequest"
+	RequestTypeMessageReceived  = "Messaging.MessageReceived"
+)
+
+// The skill lifecycle events that Alexa sends to your endpoint when users enable/disable your skill,
+// grant permissions, or link their account. You must subscribe to these events in your skill
+// manifest in order to receive them.
+//
+// See: https://developer.amazon.com/en-US/docs/alexa/smapi/skill-events-in-alexa-skills.html
+const (
+	RequestTypeSkillEnabled            = "AlexaSkillEvent.SkillEnabled"
+	RequestTypeSkillDisabled           = "AlexaSkillEvent.SkillDisabled"
+	RequestTypeSkillPermissionAccepted = "AlexaSkillEvent.SkillPermissionAccepted"
+	RequestTypeSkillPermissionChanged  = "AlexaSkillEvent.SkillPermissionChanged"
+	RequestTypeSkillAccountLinked      = "AlexaSkillEvent.SkillAccountLinked"
 )
 
 // Request is the core data structure that encapsulates all of the different pieces of data
@@ -14,9 +35,63 @@ const (
 // This struct is an adaptation of the one provided by: https://github.com/arienmalec/alexa-go
 type Request struct {
 	Version string         `json:"version"`
-	Session requestSession `json:"session"`
+	Session Session        `json:"session"`
 	Body    requestBody    `json:"request"`
 	Context requestContext `json:"context"`
+
+	// templates are the skill's catalog/settings so that responses can speak messages by ID.
+	templates templateSettings
+	// data is the per-request data that middleware attached for your handlers and templates.
+	data map[string]interface{}
+}
+
+// WithData returns a copy of the request w/ the extra value attached. Middleware can use this to hand
+// data to your handlers (see `Data()`), and it's available to all of your templates, so your
+// templates can use "{{.Data.user.Name}}" when your middleware does this:
+//
+//	return next(ctx, request.WithData("user", user))
+func (r Request) WithData(key string, value interface{}) Request {
+	data := make(map[string]interface{}, len(r.data)+1)
+	for k, v := range r.data {
+		data[k] = v
+	}
+	data[key] = value
+	r.data = data
+	return r
+}
+
+// Data returns the value that middleware attached to the request using `WithData()`.
+func (r Request) Data(key string) interface{} {
+	return r.data[key]
+}
+
+// device describes the capabilities of the user's device for templates.
+func (r Request) device() speech.Device {
+	device := speech.Device{}
+	for name := range r.Context.System.Device.SupportedInterfaces {
+		device.Interfaces = append(device.Interfaces, name)
+	}
+	sort.Strings(device.Interfaces)
+	return device
+}
+
+// SupportsInterface returns true if the user's device supports the given interface such as
+// "AudioPlayer", "Display", or "Alexa.Presentation.APL".
+func (r Request) SupportsInterface(interfaceName string) bool {
+	_, ok := r.Context.System.Device.SupportedInterfaces[interfaceName]
+	return ok
+}
+
+// message looks up the template w/ the given ID in the skill's catalog.
+func (r Request) message(id string) (speech.Template, error) {
+	if r.templates.catalog == nil {
+		return speech.Template{}, errors.New("golexa: no catalog registered w/ the skill")
+	}
+	template, ok := r.templates.catalog.Template(id)
+	if !ok {
+		return speech.Template{}, errors.New("golexa: unknown message '" + id + "'")
+	}
+	return template, nil
 }
 
 // UserID traverses the request structure to extract the id of the Amazon/Alexa user making the call.
@@ -53,6 +128,24 @@ func (r Request) Language() language.Tag {
 	return language.AmericanEnglish
 }
 
+// BindSlots copies the intent's slot values into the struct that 'dst' points to, resolving dates
+// using the language of the request (see `Slots.Bind()` for the details).
+func (r Request) BindSlots(dst interface{}) error {
+	if r.Body.Intent == nil {
+		return errors.New("golexa: request does not contain an intent")
+	}
+	return r.Body.Intent.Slots.BindLanguage(dst, r.Language())
+}
+
+// DecodeMessage unmarshals the data your background job sent through the Skill Messaging API
+// into the given value. This only works for "Messaging.MessageReceived" requests.
+func (r Request) DecodeMessage(value interface{}) error {
+	if len(r.Body.Message) == 0 {
+		return errors.New("golexa: request does not contain a skill message")
+	}
+	return json.Unmarshal(r.Body.Message, value)
+}
+
 // Application identifies the skill whose interaction model was used to invoke this request.
 type Application struct {
 	ID string `json:"applicationId,omitempty"`
@@ -70,7 +163,9 @@ type Device struct {
 	SupportedInterfaces map[string]interface{} `json:"supportedInterfaces"`
 }
 
-type requestSession struct {
+// Session contains the information that Alexa tracks across the back-and-forth interactions that
+// make up a single conversation w/ the user, such as the session attributes you sent back previously.
+type Session struct {
 	New         bool                   `json:"new"`
 	ID          string                 `json:"sessionId,omitempty"`
 	Application Application            `json:"application"`
@@ -91,6 +186,60 @@ type requestBody struct {
 	Intent      *intentRequest `json:"intent,omitempty"`
 	Reason      string         `json:"reason,omitempty"`
 	DialogState string         `json:"dialogState,omitempty"`
+
+	// These are only populated for "AlexaSkillEvent.XXX" requests.
+	EventCreationTime   string          `json:"eventCreationTime,omitempty"`
+	EventPublishingTime string          `json:"eventPublishingTime,omitempty"`
+	SkillEvent          *skillEventBody `json:"body,omitempty"`
+
+	// Message is only populated for "Messaging.MessageReceived" requests. Use `Request.DecodeMessage()`
+	// to unmarshal it into the same structure your background job sent.
+	Message json.RawMessage `json:"message,omitempty"`
+}
+
+// Permission is a single scope that the user granted to your skill (e.g. "alexa::alerts:reminders:skill:readwrite").
+type Permission struct {
+	Scope string `json:"scope"`
+}
+
+type skillEventBody struct {
+	// AccessToken is only available on "SkillAccountLinked" events.
+	AccessToken string `json:"accessToken,omitempty"`
+	// AcceptedPermissions is only available on "SkillPermissionAccepted" and "SkillPermissionChanged" events.
+	AcceptedPermissions []Permission `json:"acceptedPermissions,omitempty"`
+	// AcceptedPersonPermissions is only available on "SkillPermissionAccepted" and "SkillPermissionChanged" events.
+	AcceptedPersonPermissions []Permission `json:"acceptedPersonPermissions,omitempty"`
+	// UserInformationPersistenceStatus is only available on "SkillDisabled" events. It's "PERSISTED" when the
+	// user might re-enable the skill w/ the same user id, so you should keep their data (see `UserDataPersisted()`).
+	UserInformationPersistenceStatus string `json:"userInformationPersistenceStatus,omitempty"`
+}
+
+// The possible values for the UserInformationPersistenceStatus of a "SkillDisabled" event.
+const (
+	PersistenceStatusPersisted    = "PERSISTED"
+	PersistenceStatusNotPersisted = "NOT_PERSISTED"
+)
+
+// UserDataPersisted returns true if Alexa is holding onto the user's id after they disabled your skill,
+// so they'll get the same id if they re-enable it. When this is false, the user id is gone for good and
+// you should clean up any data you stored for them.
+func (body skillEventBody) UserDataPersisted() bool {
+	return body.UserInformationPersistenceStatus == PersistenceStatusPersisted
+}
+
+// HasPermission returns true if the user accepted the given permission scope as part of this event.
+func (body skillEventBody) HasPermission(scope string) bool {
+	for _, permission := range body.AcceptedPermissions {
+		if permission.Scope == scope {
+			return true
+		}
+	}
+	for _, permission := range body.AcceptedPersonPermissions {
+		if permission.Scope == scope {
+			return true
+		}
+	}
+	return false
 }
 
 var supportedLanguages = map[string]language.Tag{
@@ -130,7 +279,6 @@ type systemContext struct {
 	APIAccessToken string      `json:"apiAccessToken"`
 	Application    Application `json:"application,omitempty"`
 	ApiEndpoint    string      `json:"apiEndpoint"`
-	ApiAccessToken string      `json:"apiAccessToken"`
 }
 
 type audioPlayerContext struct {
@@ -159,3 +307,38 @@ func NewIntentRequest(intentName string, slots Slots) Request {
 		},
 	}
 }
+
+// NewSkillEventRequest creates a minimal request instance you can use to write unit
+// tests for your skill lifecycle event handlers (e.g. RequestTypeSkillDisabled).
+func NewSkillEventRequest(eventType string, userID string) Request {
+	return Request{
+		Version: "1.0",
+		Context: requestContext{
+			System: systemContext{
+				User: User{ID: userID},
+			},
+		},
+		Body: requestBody{
+			Type:       eventType,
+			SkillEvent: &skillEventBody{},
+		},
+	}
+}
+
+// NewMessageRequest creates a minimal request instance you can use to write unit tests for
+// your skill message handler. The message is marshaled to JSON just like the Skill Messaging API would.
+func NewMessageRequest(userID string, message interface{}) Request {
+	data, _ := json.Marshal(message)
+	return Request{
+		Version: "1.0",
+		Context: requestContext{
+			System: systemContext{
+				User: User{ID: userID},
+			},
+		},
+		Body: requestBody{
+			Type:    RequestTypeMessageReceived,
+			Message: data,
+		},
+	}
+}
@@ -2,6 +2,7 @@ package golexa_test
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/robsignorelli/golexa"
@@ -243,3 +244,321 @@ func (suite RequestSuite) TestJSON_MultipleSlots() {
 	suite.Equal("video games", req.Body.Intent.Slots.Resolve("hobby"),
 		"Should have resolve the proper slot value for slots in the request")
 }
+
+func (suite RequestSuite) TestJSON_MultiValueSlot() {
+	var input = `{
+		"version": "1.0",
+		"request": {
+			"type": "IntentRequest",
+			"requestId": "request.890",
+			"timestamp": "2019-03-16T19:46:38Z",
+			"locale": "en-US",
+			"intent": {
+				"name": "AddItemsIntent",
+				"confirmationStatus": "NONE",
+				"slots": {
+					"items": {
+						"name": "items",
+						"confirmationStatus": "NONE",
+						"source": "USER",
+						"slotValue": {
+							"type": "List",
+							"values": [
+								{
+									"type": "Simple",
+									"value": "eggs"
+								},
+								{
+									"type": "Simple",
+									"value": "two percent",
+									"resolutions": {
+										"resolutionsPerAuthority": [
+											{
+												"authority": "authority.99",
+												"status": {
+													"code": "ER_SUCCESS_MATCH"
+												},
+												"values": [
+													{
+														"value": {
+															"name": "milk",
+															"id": "MILK"
+														}
+													}
+												]
+											}
+										]
+									}
+								}
+							]
+						}
+					}
+				}
+			}
+		}
+	}`
+	req := suite.parseJSON(input)
+	slot := req.Body.Intent.Slots["items"]
+	suite.Equal("USER", slot.Source,
+		"Should populate the slot source")
+	suite.Require().NotNil(slot.SlotValue,
+		"Should populate the slot value")
+	suite.Equal("List", slot.SlotValue.Type,
+		"Should populate the slot value type")
+
+	values := slot.Values()
+	suite.Require().Len(values, 2,
+		"Should populate each of the values in the list")
+	suite.Equal("eggs", values[0].Resolve(),
+		"Should resolve values w/o resolutions to their spoken value")
+	suite.Equal("two percent", values[1].Value,
+		"Should populate the spoken value of each item")
+	suite.Equal("milk", values[1].Resolve(),
+		"Should resolve each value using its own resolutions")
+}
+
+func (suite RequestSuite) TestJSON_EntityResolution() {
+	var input = `{
+		"version": "1.0",
+		"request": {
+			"type": "IntentRequest",
+			"requestId": "request.890",
+			"timestamp": "2019-03-16T19:46:38Z",
+			"locale": "en-US",
+			"intent": {
+				"name": "RemoveItemIntent",
+				"confirmationStatus": "NONE",
+				"slots": {
+					"item": {
+						"name": "item",
+						"value": "laundry",
+						"resolutions": {
+							"resolutionsPerAuthority": [
+								{
+									"authority": "amzn1.er-authority.echo-sdk.amzn1.ask.skill.123.TodoItem",
+									"status": {
+										"code": "ER_SUCCESS_MATCH"
+									},
+									"values": [
+										{ "value": { "name": "chores", "id": "STATIC_CHORES" } }
+									]
+								},
+								{
+									"authority": "amzn1.er-authority.echo-sdk.dynamic.amzn1.ask.skill.123.TodoItem",
+									"status": {
+										"code": "ER_SUCCESS_MATCH"
+									},
+									"values": [
+										{ "value": { "name": "do the laundry", "id": "item.456" } },
+										{ "value": { "name": "fold the laundry", "id": "item.789" } }
+									]
+								}
+							]
+						}
+					},
+					"other": {
+						"name": "other",
+						"value": "fishing",
+						"resolutions": {
+							"resolutionsPerAuthority": [
+								{
+									"authority": "amzn1.er-authority.echo-sdk.amzn1.ask.skill.123.TodoItem",
+									"status": {
+										"code": "ER_SUCCESS_NO_MATCH"
+									}
+								},
+								{
+									"authority": "amzn1.er-authority.echo-sdk.dynamic.amzn1.ask.skill.123.TodoItem",
+									"status": {
+										"code": "ER_ERROR_TIMEOUT"
+									}
+								}
+							]
+						}
+					}
+				}
+			}
+		}
+	}`
+	req := suite.parseJSON(input)
+	item := req.Body.Intent.Slots["item"]
+
+	authorities := item.Authorities()
+	suite.Require().Len(authorities, 2,
+		"Should expose every authority")
+	suite.False(authorities[0].Dynamic(),
+		"Should identify static authorities")
+	suite.True(authorities[1].Dynamic(),
+		"Should identify dynamic authorities")
+
+	suite.Equal("item.456", item.ResolveID(),
+		"Should prefer dynamic matches over static matches when resolving the id")
+	suite.Equal("item.456", req.Body.Intent.Slots.ResolveID("item"),
+		"Should prefer dynamic matches over static matches when resolving the id")
+	suite.Equal("do the laundry", item.Resolve(),
+		"Should prefer dynamic matches over static matches when resolving the value")
+
+	matches := item.Matches()
+	suite.Require().Len(matches, 3,
+		"Should include every matched value from every authority")
+	suite.Equal(golexa.ResolvedValue{
+		Name:      "fold the laundry",
+		ID:        "item.789",
+		Authority: "amzn1.er-authority.echo-sdk.dynamic.amzn1.ask.skill.123.TodoItem",
+		Dynamic:   true,
+	}, matches[1],
+		"Should include the id and authority of each match")
+	suite.Equal("STATIC_CHORES", matches[2].ID,
+		"Should include static matches after dynamic matches")
+
+	other := req.Body.Intent.Slots["other"]
+	suite.Equal(golexa.ResolutionSuccessNoMatch, other.Authorities()[0].Status.Code,
+		"Should expose the status code of each authority")
+	suite.Equal(golexa.ResolutionErrorTimeout, other.Authorities()[1].Status.Code,
+		"Should expose the status code of each authority")
+	suite.Equal("", other.ResolveID(),
+		"Should not resolve an id when nothing matched")
+	suite.Len(other.Matches(), 0,
+		"Should not have any matches when nothing matched")
+	suite.Equal("fishing", other.Resolve(),
+		"Should resolve to the spoken value when nothing matched")
+}
+
+func (suite RequestSuite) TestJSON_SkillAccountLinked() {
+	var input = `{
+		"version": "1.0",
+		"context": {
+			"System": {
+				"application": {
+					"applicationId": "skill.456"
+				},
+				"user": {
+					"userId": "account.789",
+					"accessToken": "token.acb"
+				},
+				"apiEndpoint": "https://api.amazonalexa.com",
+				"apiAccessToken": "api.token"
+			}
+		},
+		"request": {
+			"type": "AlexaSkillEvent.SkillAccountLinked",
+			"requestId": "request.890",
+			"timestamp": "2019-03-16T19:46:38Z",
+			"eventCreationTime": "2019-03-16T19:46:30Z",
+			"eventPublishingTime": "2019-03-16T19:46:35Z",
+			"body": {
+				"accessToken": "token.acb"
+			}
+		}
+	}`
+	req := suite.parseJSON(input)
+	suite.Equal(golexa.RequestTypeSkillAccountLinked, req.Body.Type,
+		"Should populate the event type properly")
+	suite.Equal("2019-03-16T19:46:30Z", req.Body.EventCreationTime,
+		"Should populate the event creation time properly")
+	suite.Equal("2019-03-16T19:46:35Z", req.Body.EventPublishingTime,
+		"Should populate the event publishing time properly")
+	suite.Equal("api.token", req.Context.System.APIAccessToken,
+		"Should populate the API access token properly")
+
+	suite.Require().NotNil(req.Body.SkillEvent,
+		"Should populate non-nil skill event body")
+	suite.Equal("token.acb", req.Body.SkillEvent.AccessToken,
+		"Should populate the linked access token properly")
+}
+
+func (suite RequestSuite) TestJSON_SkillDisabled() {
+	var input = `{
+		"version": "1.0",
+		"context": {
+			"System": {
+				"application": {
+					"applicationId": "skill.456"
+				},
+				"user": {
+					"userId": "account.789"
+				}
+			}
+		},
+		"request": {
+			"type": "AlexaSkillEvent.SkillDisabled",
+			"requestId": "request.890",
+			"timestamp": "2019-03-16T19:46:38Z",
+			"eventCreationTime": "2019-03-16T19:46:30Z",
+			"eventPublishingTime": "2019-03-16T19:46:35Z",
+			"body": {
+				"userInformationPersistenceStatus": "NOT_PERSISTED"
+			}
+		}
+	}`
+	req := suite.parseJSON(input)
+	suite.Equal(golexa.RequestTypeSkillDisabled, req.Body.Type,
+		"Should populate the event type properly")
+	suite.Equal("account.789", req.UserID(),
+		"Should populate the user whose data you should clean up")
+
+	suite.Require().NotNil(req.Body.SkillEvent,
+		"Should populate non-nil skill event body")
+	suite.Equal(golexa.PersistenceStatusNotPersisted, req.Body.SkillEvent.UserInformationPersistenceStatus,
+		"Should populate the persistence status properly")
+	suite.False(req.Body.SkillEvent.UserDataPersisted(),
+		"Should not report the user data as persisted when it's NOT_PERSISTED")
+
+	req = suite.parseJSON(strings.Replace(input, "NOT_PERSISTED", "PERSISTED", 1))
+	suite.True(req.Body.SkillEvent.UserDataPersisted(),
+		"Should report the user data as persisted when it's PERSISTED")
+}
+
+func (suite RequestSuite) TestJSON_SkillPermissionAccepted() {
+	var input = `{
+		"version": "1.0",
+		"request": {
+			"type": "AlexaSkillEvent.SkillPermissionAccepted",
+			"requestId": "request.890",
+			"timestamp": "2019-03-16T19:46:38Z",
+			"body": {
+				"acceptedPermissions": [
+					{"scope": "alexa::alerts:reminders:skill:readwrite"}
+				],
+				"acceptedPersonPermissions": [
+					{"scope": "alexa::profile:given_name:read"}
+				]
+			}
+		}
+	}`
+	req := suite.parseJSON(input)
+	suite.Require().NotNil(req.Body.SkillEvent,
+		"Should populate non-nil skill event body")
+	suite.Require().Len(req.Body.SkillEvent.AcceptedPermissions, 1,
+		"Should populate all accepted permissions")
+	suite.Require().Len(req.Body.SkillEvent.AcceptedPersonPermissions, 1,
+		"Should populate all accepted person permissions")
+
+	suite.True(req.Body.SkillEvent.HasPermission("alexa::alerts:reminders:skill:readwrite"),
+		"Should find permissions that were accepted")
+	suite.True(req.Body.SkillEvent.HasPermission("alexa::profile:given_name:read"),
+		"Should find person permissions that were accepted")
+	suite.False(req.Body.SkillEvent.HasPermission("alexa::devices:all:notifications:write"),
+		"Should not find permissions that were not accepted")
+}
+
+func (suite RequestSuite) TestData() {
+	request := golexa.NewIntentRequest("Foo", golexa.NewSlots())
+	withUser := request.WithData("user", "Sam")
+	withBoth := withUser.WithData("plan", "premium")
+
+	suite.Nil(request.Data("user"), "WithData should not mutate the original request")
+	suite.Equal("Sam", withUser.Data("user"), "Should return attached data")
+	suite.Nil(withUser.Data("plan"), "WithData should not mutate the original request")
+	suite.Equal("Sam", withBoth.Data("user"), "Should keep previously attached data")
+	suite.Equal("premium", withBoth.Data("plan"), "Should return attached data")
+}
+
+func (suite RequestSuite) TestSupportsInterface() {
+	request := golexa.NewIntentRequest("Foo", golexa.NewSlots())
+	suite.False(request.SupportsInterface("Display"), "Should not support interfaces w/o any device info")
+
+	request.Context.System.Device.SupportedInterfaces = map[string]interface{}{"AudioPlayer": map[string]interface{}{}}
+	suite.True(request.SupportsInterface("AudioPlayer"), "Should support interfaces the device listed")
+	suite.False(request.SupportsInterface("Display"), "Should not support interfaces the device didn't list")
+}
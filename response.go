@@ -1,12 +1,14 @@
 package golexa
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/robsignorelli/golexa/speech"
-	"github.com/sirupsen/logrus"
+	"github.com/robsignorelli/golexa/ssml"
 )
 
 // NewResponse create a bare-bones response instance that you can continue to expand on
@@ -30,23 +32,128 @@ func (r Response) EndSession(flag bool) Response {
 // Speak indicates w/ you want the Alexa voice to dictate back to the user. You can provide
 // plain text or SSML.
 func (r Response) Speak(textOrSSML string) Response {
+	speech, err := wrapSSML(textOrSSML)
+	if err != nil {
+		r = r.fail(err)
+	}
 	r.Body.OutputSpeech = &intentResponse{
-		SSML: wrapSSML(textOrSSML),
+		SSML: speech,
 	}
 	return r
 }
 
+// SpeakTemplate evaluates the template in the language of the request and speaks the result. When
+// the template has variants, the one we picked is remembered in the session attributes so that
+// strategies like `speech.RoundRobin` work across the user's entire conversation.
 func (r Response) SpeakTemplate(template speech.Template, value interface{}) Response {
+	r, textOrSSML, err := r.evalTemplate(template, value)
+	if err != nil {
+		r = r.fail(fmt.Errorf("golexa: unable to speak template: %v", err))
+		r, textOrSSML = r.fallback()
+		return r.Speak(textOrSSML)
+	}
+	return r.Speak(textOrSSML)
+}
+
+// SpeakMessage works just like `SpeakTemplate()`, but speaks the template w/ the given message ID
+// from the skill's catalog (see `Skill.Catalog()`).
+func (r Response) SpeakMessage(id string, value interface{}) Response {
+	template, err := r.Request.message(id)
+	if err != nil {
+		r = r.fail(fmt.Errorf("golexa: unable to speak message: %v", err))
+		var textOrSSML string
+		r, textOrSSML = r.fallback()
+		return r.Speak(textOrSSML)
+	}
+	return r.SpeakTemplate(template, value)
+}
+
+// RepromptTemplate evaluates the template in the language of the request and uses it as the
+// reprompt (see `Reprompt()`).
+func (r Response) RepromptTemplate(template speech.Template, value interface{}) Response {
+	r, textOrSSML, err := r.evalTemplate(template, value)
+	if err != nil {
+		r = r.fail(fmt.Errorf("golexa: unable to reprompt template: %v", err))
+		r, textOrSSML = r.fallback()
+		return r.Reprompt(textOrSSML)
+	}
+	return r.Reprompt(textOrSSML)
+}
+
+// RepromptMessage works just like `RepromptTemplate()`, but uses the template w/ the given message ID
+// from the skill's catalog (see `Skill.Catalog()`).
+func (r Response) RepromptMessage(id string, value interface{}) Response {
+	template, err := r.Request.message(id)
+	if err != nil {
+		r = r.fail(fmt.Errorf("golexa: unable to reprompt message: %v", err))
+		var textOrSSML string
+		r, textOrSSML = r.fallback()
+		return r.Reprompt(textOrSSML)
+	}
+	return r.RepromptTemplate(template, value)
+}
+
+// apology is what Alexa says when we can't evaluate one of your templates (or your fallback).
+const apology = "I'm sorry. I seem to have trouble with words, today."
+
+// fail remembers the error (e.g. a template that didn't evaluate) so that `Err()` and `Ok()` can report it. We
+// don't log it here; that's up to you (or the Logger middleware). We only hang onto the first one since that's
+// usually the one that caused the rest.
+func (r Response) fail(err error) Response {
+	if r.err == nil {
+		r.err = err
+	}
+	return r
+}
+
+// fallback evaluates the skill's fallback template (see `Skill.TemplateFallback()`), resorting to our
+// canned apology if there isn't one or if it fails, too. A broken fallback is tacked onto the error
+// that got us here so that `Err()` still tells you about both.
+func (r Response) fallback() (Response, string) {
+	if r.Request.templates.fallback == nil {
+		return r, apology
+	}
+	r, textOrSSML, err := r.evalTemplate(*r.Request.templates.fallback, nil)
+	if err != nil {
+		r.err = fmt.Errorf("%v (unable to speak fallback template: %v)", r.err, err)
+		return r, apology
+	}
+	return r, textOrSSML
+}
+
+// evalTemplate evaluates the template in the language of the request, returning a copy of the response
+// that remembers which variant we picked in the session attributes.
+func (r Response) evalTemplate(template speech.Template, value interface{}) (Response, string, error) {
+	variants := r.variantState()
 	textOrSSML, err := template.Eval(speech.TemplateContext{
 		Language: r.Request.Language(),
 		Now:      time.Now(),
 		Value:    value,
+		Request:  r.Request,
+		Session:  r.Request.Session.Attributes,
+		Device:   r.Request.device(),
+		Data:     r.Request.data,
+		Variants: variants,
 	})
 	if err != nil {
-		logrus.Errorf("unable to speak template: %v", err)
-		return r.Speak("I'm sorry. I seem to have trouble with words, today.")
+		return r, "", err
 	}
-	return r.Speak(textOrSSML)
+	if len(variants) > 0 {
+		r = r.SessionAttribute(sessionKeyVariants, map[string]interface{}(variants))
+	}
+	return r, textOrSSML, nil
+}
+
+// SessionAttribute stores a value that Alexa will send back to you on the next request in this
+// session (see `Request.Session.Attributes`).
+func (r Response) SessionAttribute(key string, value interface{}) Response {
+	attributes := make(map[string]interface{}, len(r.SessionAttributes)+1)
+	for k, v := range r.SessionAttributes {
+		attributes[k] = v
+	}
+	attributes[key] = value
+	r.SessionAttributes = attributes
+	return r
 }
 
 // SimpleCard customizes what the user should see on an Echo device that supports a screen
@@ -60,6 +167,46 @@ func (r Response) SimpleCard(title, text string) Response {
 	return r
 }
 
+// SimpleCardTemplate evaluates the title and text templates in the language of the request and
+// displays them in a simple card (see `SimpleCard()`). Cards can't display SSML, so any tags are
+// stripped out, letting you use the same template for your speech and your card. If either template
+// fails, we log the error and leave the card off rather than showing the user something broken.
+func (r Response) SimpleCardTemplate(title speech.Template, text speech.Template, value interface{}) Response {
+	r, titleSSML, err := r.evalTemplate(title, value)
+	if err != nil {
+		return r.fail(fmt.Errorf("golexa: unable to display card template: %v", err))
+	}
+	r, textSSML, err := r.evalTemplate(text, value)
+	if err != nil {
+		return r.fail(fmt.Errorf("golexa: unable to display card template: %v", err))
+	}
+	return r.SimpleCard(ssml.PlainText(titleSSML), ssml.PlainText(textSSML))
+}
+
+// SimpleCardMessage works just like `SimpleCardTemplate()`, but uses the templates w/ the given message
+// IDs from the skill's catalog (see `Skill.Catalog()`).
+func (r Response) SimpleCardMessage(titleID string, textID string, value interface{}) Response {
+	title, err := r.Request.message(titleID)
+	if err != nil {
+		return r.fail(fmt.Errorf("golexa: unable to display card message: %v", err))
+	}
+	text, err := r.Request.message(textID)
+	if err != nil {
+		return r.fail(fmt.Errorf("golexa: unable to display card message: %v", err))
+	}
+	return r.SimpleCardTemplate(title, text, value)
+}
+
+// LinkAccountCard displays a card in the Alexa app that prompts the user to link their account
+// with your system. You should use this in conjunction w/ `Speak()` to tell the user to check
+// their Alexa app whenever a feature requires account linking.
+func (r Response) LinkAccountCard() Response {
+	r.Body.Card = &intentResponse{
+		Type: "LinkAccount",
+	}
+	return r
+}
+
 // ElicitSlot keeps the current session open and has the user's echo device go back into capture
 // mode. Whatever the user speaks next will be applied to the specified slot and all other slots
 // from this request will be sent along to the slot you named. You should use this in conjunction
@@ -86,13 +233,46 @@ func (r Response) ElicitSlot(intentName, slotName string) Response {
 	return r.EndSession(false)
 }
 
+// UpdateDynamicEntities teaches Alexa about slot values that are specific to this user (e.g. the items
+// in their own to-do list) so that entity resolution can match them. The entities replace any dynamic
+// entities you previously sent and stay active for the remainder of the session (up to 30 minutes).
+// You can send up to 100 entities across all slot types; if you exceed this limit or provide
+// invalid entities, the directive is not sent and `Err()` reports why.
+//
+// See: https://developer.amazon.com/en-US/docs/alexa/custom-skills/use-dynamic-entities-for-customized-interactions.html
+func (r Response) UpdateDynamicEntities(types ...DynamicSlotType) Response {
+	if err := ValidateDynamicEntities(types...); err != nil {
+		return r.fail(fmt.Errorf("golexa: unable to update dynamic entities: %v", err))
+	}
+	r.Body.Directives = append(r.Body.Directives, directive{
+		Type:           "Dialog.UpdateDynamicEntities",
+		UpdateBehavior: "REPLACE",
+		Types:          types,
+	})
+	return r
+}
+
+// ClearDynamicEntities removes all of the dynamic entities you previously sent during this session so
+// that entity resolution only considers the static values in your interaction model.
+func (r Response) ClearDynamicEntities() Response {
+	r.Body.Directives = append(r.Body.Directives, directive{
+		Type:           "Dialog.UpdateDynamicEntities",
+		UpdateBehavior: "CLEAR",
+	})
+	return r
+}
+
 // Reprompt should be used in conjunction w/ an `ElicitSlot()` call. If the user doesn't say anything
 // when they're asked to fill in one of the slots, this will be a second audio prompt to try to get them
 // to say something. If the user actually responded the first time, they won't actually hear this.
 func (r Response) Reprompt(textOrSSML string) Response {
+	speech, err := wrapSSML(textOrSSML)
+	if err != nil {
+		r = r.fail(err)
+	}
 	r.Body.Reprompt = &reprompt{
 		OutputSpeech: intentResponse{
-			SSML: wrapSSML(textOrSSML),
+			SSML: speech,
 		},
 	}
 	return r
@@ -100,10 +280,22 @@ func (r Response) Reprompt(textOrSSML string) Response {
 
 // Ok simply returns the Response in its current state and a 'nil' error. This is a convenience so
 // that you can build your response at the end of your handlers which require a response and an error.
+// When the skill uses strict templates (see `Skill.StrictTemplates()`), this returns the first error
+// (see `Err()`) instead of 'nil' so the failure doesn't get buried in your logs.
 func (r Response) Ok() (Response, error) {
+	if r.err != nil && r.Request.templates.strict {
+		return r, r.err
+	}
 	return r, nil
 }
 
+// Err returns the first error we ran into while building this response (e.g. evaluating templates or
+// validating dynamic entities), or 'nil' if everything worked. This works regardless of whether the
+// skill uses strict templates, so you can decide for yourself what to do about it.
+func (r Response) Err() error {
+	return r.err
+}
+
 // Fail should be used in only the most dire of unrecoverable circumstances. It will respond
 // with no Alexa instructions and an error w/ the given message. You should NOT use this in
 // instances where your skill can't give a meaningful response to a question. It should only
@@ -126,6 +318,9 @@ type Response struct {
 	Version           string                 `json:"version"`
 	SessionAttributes map[string]interface{} `json:"sessionAttributes,omitempty"`
 	Body              responseBody           `json:"response"`
+
+	// err is the first failure we encountered while building the response (see `Err()`).
+	err error
 }
 
 type responseBody struct {
@@ -145,17 +340,21 @@ type intentResponse struct {
 }
 
 type directive struct {
-	Type          string         `json:"type,omitempty"`
-	SlotToElicit  string         `json:"slotToElicit,omitempty"`
-	UpdatedIntent *updatedIntent `json:"updatedIntent,omitempty"`
-	PlayBehavior  string         `json:"playBehavior,omitempty"`
-	AudioItem     struct {
-		Stream struct {
-			Token                string `json:"token,omitempty"`
-			URL                  string `json:"url,omitempty"`
-			OffsetInMilliseconds int    `json:"offsetInMilliseconds,omitempty"`
-		} `json:"stream,omitempty"`
-	} `json:"audioItem,omitempty"`
+	Type           string            `json:"type,omitempty"`
+	SlotToElicit   string            `json:"slotToElicit,omitempty"`
+	UpdatedIntent  *updatedIntent    `json:"updatedIntent,omitempty"`
+	PlayBehavior   string            `json:"playBehavior,omitempty"`
+	UpdateBehavior string            `json:"updateBehavior,omitempty"`
+	Types          []DynamicSlotType `json:"types,omitempty"`
+	AudioItem      *audioItem        `json:"audioItem,omitempty"`
+}
+
+type audioItem struct {
+	Stream struct {
+		Token                string `json:"token,omitempty"`
+		URL                  string `json:"url,omitempty"`
+		OffsetInMilliseconds int    `json:"offsetInMilliseconds,omitempty"`
+	} `json:"stream,omitempty"`
 }
 
 type updatedIntent struct {
@@ -164,16 +363,122 @@ type updatedIntent struct {
 	Slots              Slots  `json:"slots,omitempty"`
 }
 
+// maxDynamicEntities is the most entities that Alexa accepts across all slot types in a single directive.
+const maxDynamicEntities = 100
+
+// DynamicSlotType is one of the custom slot types in your interaction model along with the
+// user-specific values that Alexa should consider when resolving it.
+type DynamicSlotType struct {
+	Name   string          `json:"name"`
+	Values []DynamicEntity `json:"values"`
+}
+
+// NewDynamicSlotType creates a slot type w/ the given dynamic entities.
+func NewDynamicSlotType(name string, values ...DynamicEntity) DynamicSlotType {
+	return DynamicSlotType{Name: name, Values: values}
+}
+
+// DynamicEntity is a single value for a dynamic slot type. When the user says the value or any of
+// its synonyms, the slot will resolve to this entity's value and id.
+type DynamicEntity struct {
+	ID       string
+	Value    string
+	Synonyms []string
+}
+
+// NewDynamicEntity creates a single entity value that you can include in a DynamicSlotType.
+func NewDynamicEntity(id, value string, synonyms ...string) DynamicEntity {
+	return DynamicEntity{ID: id, Value: value, Synonyms: synonyms}
+}
+
+// MarshalJSON encodes the entity in the nested format that Alexa expects.
+func (entity DynamicEntity) MarshalJSON() ([]byte, error) {
+	type name struct {
+		Value    string   `json:"value"`
+		Synonyms []string `json:"synonyms,omitempty"`
+	}
+	return json.Marshal(struct {
+		ID   string `json:"id,omitempty"`
+		Name name   `json:"name"`
+	}{
+		ID:   entity.ID,
+		Name: name{Value: entity.Value, Synonyms: entity.Synonyms},
+	})
+}
+
+// ValidateDynamicEntities checks that the slot types/entities are acceptable to Alexa. You don't need
+// to call this yourself since `UpdateDynamicEntities()` does it for you, but it's handy if you want
+// to detect problems before building your response.
+func ValidateDynamicEntities(types ...DynamicSlotType) error {
+	if len(types) == 0 {
+		return errors.New("no slot types provided")
+	}
+
+	total := 0
+	for _, slotType := range types {
+		if slotType.Name == "" {
+			return errors.New("slot type is missing a name")
+		}
+		for _, entity := range slotType.Values {
+			if strings.TrimSpace(entity.Value) == "" {
+				return fmt.Errorf("slot type '%s' has an entity w/o a value", slotType.Name)
+			}
+		}
+		total += len(slotType.Values)
+	}
+	if total > maxDynamicEntities {
+		return fmt.Errorf("too many entities: %d (max %d)", total, maxDynamicEntities)
+	}
+	return nil
+}
+
 type reprompt struct {
 	OutputSpeech intentResponse `json:"outputSpeech,omitempty"`
 }
 
+// sessionKeyVariants is the session attribute where we track which template variants the user heard last.
+const sessionKeyVariants = "golexa.variants"
+
+// sessionVariants tracks the last variant spoken for each template (see `speech.VariantState`). The
+// indexes will be float64 values when they come from the request since they've been through JSON.
+type sessionVariants map[string]interface{}
+
+func (s sessionVariants) LastVariant(key string) (int, bool) {
+	switch index := s[key].(type) {
+	case int:
+		return index, true
+	case float64:
+		return int(index), true
+	default:
+		return 0, false
+	}
+}
+
+func (s sessionVariants) RememberVariant(key string, index int) {
+	s[key] = index
+}
+
+// variantState copies the variants we've already tracked for this response (or the incoming
+// session if we haven't spoken any templates yet) so that we don't mutate any other responses.
+func (r Response) variantState() sessionVariants {
+	previous, ok := r.SessionAttributes[sessionKeyVariants].(map[string]interface{})
+	if !ok {
+		previous, _ = r.Request.Session.Attributes[sessionKeyVariants].(map[string]interface{})
+	}
+	state := sessionVariants{}
+	for key, index := range previous {
+		state[key] = index
+	}
+	return state
+}
+
 // wrapSSML ensures that the text you want Alexa to speak is SSML. It allows you to
 // utilize the same attribute in the response whether you are simply giving plain text
-// or you built your own SSML markup.
-func wrapSSML(textOrSSML string) string {
-	if strings.HasPrefix(textOrSSML, "<speak") {
-		return textOrSSML
+// or you built your own SSML markup. See `ssml.Wrap()` for how we handle broken markup.
+func wrapSSML(textOrSSML string) (string, error) {
+	doc, err := ssml.Wrap(textOrSSML)
+	if err != nil {
+		return doc, fmt.Errorf("golexa: invalid speech: %v", err)
 	}
-	return "<speak>" + textOrSSML + "</speak>"
+	return doc, nil
 }
@@ -1,6 +1,8 @@
 package golexa_test
 
 import (
+	"encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/robsignorelli/golexa"
@@ -73,6 +75,43 @@ func (suite ResponseSuite) TestSpeak() {
 	res.Speak("Goodbye")
 	suite.Equal("<speak>The cow goes woof</speak>", res.Body.OutputSpeech.SSML,
 		"Speak does not mutate the original response")
+
+	res = res.Speak(`Wait for it <break time="1s"/> woof`)
+	suite.Equal(`<speak>Wait for it <break time="1s"/> woof</speak>`, res.Body.OutputSpeech.SSML,
+		"Should leave valid SSML tags in plain text alone.")
+
+	res = res.Speak("Tom & Jerry <3")
+	suite.Equal("<speak>Tom &amp; Jerry &lt;3</speak>", res.Body.OutputSpeech.SSML,
+		"Should escape plain text that isn't valid SSML.")
+
+	res = res.Reprompt("Tom & Jerry?")
+	suite.Equal("<speak>Tom &amp; Jerry?</speak>", res.Body.Reprompt.OutputSpeech.SSML,
+		"Should escape reprompt text that isn't valid SSML.")
+	suite.NoError(res.Err(), "Should not report text that we were able to escape")
+
+	res = res.Speak("Hi <emphasis>there</emphasis> & bye")
+	suite.Equal("<speak>Hi <emphasis>there</emphasis> &amp; bye</speak>", res.Body.OutputSpeech.SSML,
+		"Should only escape the stray characters, not the valid markup.")
+
+	res = res.Speak("<speak>Tom & Jerry</speak>")
+	suite.Equal("<speak>Tom &amp; Jerry</speak>", res.Body.OutputSpeech.SSML,
+		"Should escape the stray characters in your own <speak> documents.")
+	suite.NoError(res.Err(), "Should not report text that we were able to escape")
+
+	res = res.Speak("x<y")
+	suite.Equal("<speak>x&lt;y</speak>", res.Body.OutputSpeech.SSML,
+		"Should escape a less-than sign that isn't followed by a supported tag.")
+	suite.NoError(res.Err(), "Should not report plain text w/ a less-than sign")
+
+	res = res.Speak(`<speak>Tom <emphasis level="super">&</emphasis> Jerry</speak>`)
+	suite.Equal("<speak>Tom &amp; Jerry</speak>", res.Body.OutputSpeech.SSML,
+		"Should only speak the text when the SSML is still invalid.")
+	suite.Error(res.Err(), "Should report SSML that we couldn't fix")
+
+	res = golexa.NewResponse(golexa.Request{}).Reprompt("Unclosed <emphasis>tag")
+	suite.Equal("<speak>Unclosed tag</speak>", res.Body.Reprompt.OutputSpeech.SSML,
+		"Should only speak the text when the reprompt SSML is still invalid.")
+	suite.Error(res.Err(), "Should report reprompt SSML that we couldn't fix")
 }
 
 func (suite ResponseSuite) TestSpeakTemplate() {
@@ -106,6 +145,84 @@ func (suite ResponseSuite) TestSpeakTemplate() {
 		"Should evaluate the default translation when the request's local is it-IT")
 }
 
+func (suite ResponseSuite) TestSpeakTemplateVariants() {
+	t := speech.NewTemplate("One",
+		speech.WithVariants(language.AmericanEnglish, "Two", "Three"),
+		speech.WithSelection(speech.RoundRobin))
+
+	// Simulate Alexa sending back the session attributes (via JSON) on each request in the conversation.
+	req := golexa.Request{}
+	var spoken []string
+	for i := 0; i < 4; i++ {
+		res := golexa.NewResponse(req).SpeakTemplate(t, nil)
+		spoken = append(spoken, res.Body.OutputSpeech.SSML)
+
+		data, err := json.Marshal(res.SessionAttributes)
+		suite.Require().NoError(err, "Should be able to marshal session attributes")
+		suite.Require().NoError(json.Unmarshal(data, &req.Session.Attributes), "Should be able to unmarshal session attributes")
+	}
+	suite.Equal([]string{"<speak>One</speak>", "<speak>Two</speak>", "<speak>Three</speak>", "<speak>One</speak>"}, spoken,
+		"Should track the last variant in the session attributes")
+
+	res := golexa.NewResponse(golexa.Request{}).SessionAttribute("foo", "bar")
+	suite.Equal("bar", res.SessionAttributes["foo"], "Should set session attributes")
+	res.SessionAttribute("foo", "baz")
+	suite.Equal("bar", res.SessionAttributes["foo"], "SessionAttribute does not mutate the original response")
+}
+
+func (suite ResponseSuite) TestRepromptTemplate() {
+	t := speech.NewTemplate("Are you there, {{.Value}}?",
+		speech.WithTranslation(language.Spanish, "¿Estás ahí, {{.Value}}?"))
+
+	request := golexa.Request{}
+	request.Body.Locale = "es-MX"
+	res := golexa.NewResponse(request).RepromptTemplate(t, "Rob & Co")
+	suite.Equal("<speak>¿Estás ahí, Rob &amp; Co?</speak>", res.Body.Reprompt.OutputSpeech.SSML,
+		"Should evaluate the reprompt in the request's language")
+	suite.Nil(res.Body.OutputSpeech, "Should not affect the speech")
+
+	broken := speech.NewTemplate("{{.Value.Nope}}")
+	res = golexa.NewResponse(request).RepromptTemplate(broken, "Rob")
+	suite.Contains(res.Body.Reprompt.OutputSpeech.SSML, "I'm sorry", "Should apologize when the template fails")
+}
+
+func (suite ResponseSuite) TestSimpleCardTemplate() {
+	title := speech.NewTemplate("Added {{.Value}}", speech.WithTranslation(language.Spanish, "Agregado {{.Value}}"))
+	text := speech.NewTemplate(`<speak>I added <emphasis level="strong">{{.Value}}</emphasis> <break time="1s"/> to your list.</speak>`,
+		speech.WithTranslation(language.Spanish, `Agregué <emphasis level="strong">{{.Value}}</emphasis> a tu lista.`))
+
+	res := golexa.NewResponse(golexa.Request{}).SimpleCardTemplate(title, text, "Tom & Jerry")
+	suite.Equal("Added Tom & Jerry", res.Body.Card.Title, "Should evaluate the title as plain text")
+	suite.Equal("I added Tom & Jerry to your list.", res.Body.Card.Content, "Should strip SSML out of the card text")
+
+	request := golexa.Request{}
+	request.Body.Locale = "es-MX"
+	res = golexa.NewResponse(request).SimpleCardTemplate(title, text, "leche")
+	suite.Equal("Agregado leche", res.Body.Card.Title, "Should evaluate the title in the request's language")
+	suite.Equal("Agregué leche a tu lista.", res.Body.Card.Content, "Should evaluate the text in the request's language")
+
+	broken := speech.NewTemplate("{{.Value.Nope}}")
+	res = golexa.NewResponse(request).SimpleCardTemplate(title, broken, "leche")
+	suite.Nil(res.Body.Card, "Should not display a card when a template fails")
+}
+
+func (suite ResponseSuite) TestSpeakTemplateContext() {
+	t := speech.NewTemplate(`{{.Request.Body.Intent.Name}}: Welcome back, {{.Data.user}}. ` +
+		`You have {{.Session.visits}} visits. {{if .Device.HasScreen}}Check your screen.{{else}}Check the app.{{end}}`)
+
+	request := golexa.NewIntentRequest("Greet", golexa.NewSlots())
+	request.Session.Attributes = map[string]interface{}{"visits": 3}
+	request = request.WithData("user", "Sam")
+	res := golexa.NewResponse(request).SpeakTemplate(t, nil)
+	suite.Equal("<speak>Greet: Welcome back, Sam. You have 3 visits. Check the app.</speak>", res.Body.OutputSpeech.SSML,
+		"Should give the template access to the request, session, data, and device")
+
+	request.Context.System.Device.SupportedInterfaces = map[string]interface{}{"Alexa.Presentation.APL": map[string]interface{}{}}
+	res = golexa.NewResponse(request).SpeakTemplate(t, nil)
+	suite.Equal("<speak>Greet: Welcome back, Sam. You have 3 visits. Check your screen.</speak>", res.Body.OutputSpeech.SSML,
+		"Should give the template access to the device capabilities")
+}
+
 func (suite ResponseSuite) TestSimpleCard() {
 	run := func(title, text string) golexa.Response {
 		return golexa.NewResponse(golexa.Request{}).SimpleCard(title, text)
@@ -184,6 +301,13 @@ func (suite ResponseSuite) TestElicitSlot() {
 		"Directive should blank out the elicited slot")
 	suite.Equal("99", res.Body.Directives[0].UpdatedIntent.Slots["age"].Value,
 		"Directive should preserve other slots")
+
+	// Multi-value slots should be echoed back as lists.
+	res = run("Foo", "name", golexa.NewSlots(golexa.NewListSlot("items", "eggs", "milk")))
+	suite.Require().Len(res.Body.Directives, 1,
+		"Should have 1 directive on the response")
+	suite.Equal([]string{"eggs", "milk"}, res.Body.Directives[0].UpdatedIntent.Slots.ResolveValues("items"),
+		"Directive should preserve the values of multi-value slots")
 }
 
 func (suite ResponseSuite) TestReprompt() {
@@ -236,3 +360,84 @@ func (suite ResponseSuite) TestOk() {
 	suite.Equal("I said Moo.", res.Body.Card.Content,
 		"Ok should return the same response you've been constructing")
 }
+
+func (suite ResponseSuite) TestErr() {
+	res := golexa.NewResponse(golexa.Request{}).Speak("Moo")
+	suite.NoError(res.Err(), "Should not report an error when there are no templates")
+
+	res = golexa.NewResponse(golexa.Request{}).
+		SpeakTemplate(speech.NewTemplate("{{.Value.First}}"), "Moo").
+		RepromptMessage("nope", nil)
+	suite.Require().Error(res.Err(), "Should report template errors")
+	suite.Contains(res.Err().Error(), "unable to speak template", "Should report the first error")
+
+	_, err := res.Ok()
+	suite.NoError(err, "Ok should not return template errors unless the skill is strict")
+}
+
+func (suite ResponseSuite) TestLinkAccountCard() {
+	res := golexa.NewResponse(golexa.Request{}).
+		SimpleCard("Hello", "World").
+		LinkAccountCard()
+	suite.Require().NotNil(res.Body.Card,
+		"Should have a non-nil 'Card' attribute")
+	suite.Equal("LinkAccount", res.Body.Card.Type,
+		"Should set the card to 'LinkAccount'")
+	suite.Equal("", res.Body.Card.Title,
+		"Should replace any previous card")
+}
+
+func (suite ResponseSuite) TestUpdateDynamicEntities() {
+	res := golexa.NewResponse(golexa.Request{}).UpdateDynamicEntities(
+		golexa.NewDynamicSlotType("TodoItem",
+			golexa.NewDynamicEntity("item.1", "laundry", "wash clothes", "do the wash"),
+			golexa.NewDynamicEntity("item.2", "dishes")),
+	)
+	suite.Require().Len(res.Body.Directives, 1,
+		"Should add a directive to the response")
+	suite.Equal("Dialog.UpdateDynamicEntities", res.Body.Directives[0].Type,
+		"Should add an 'UpdateDynamicEntities' directive")
+
+	data, err := json.Marshal(res.Body.Directives[0])
+	suite.Require().NoError(err)
+	suite.JSONEq(`{
+		"type": "Dialog.UpdateDynamicEntities",
+		"updateBehavior": "REPLACE",
+		"types": [{
+			"name": "TodoItem",
+			"values": [
+				{"id": "item.1", "name": {"value": "laundry", "synonyms": ["wash clothes", "do the wash"]}},
+				{"id": "item.2", "name": {"value": "dishes"}}
+			]
+		}]
+	}`, string(data), "Should encode entities in the format Alexa expects")
+
+	var tooMany []golexa.DynamicEntity
+	for i := 0; i < 101; i++ {
+		tooMany = append(tooMany, golexa.NewDynamicEntity("", fmt.Sprintf("item %d", i)))
+	}
+	res = golexa.NewResponse(golexa.Request{}).UpdateDynamicEntities(golexa.NewDynamicSlotType("TodoItem", tooMany...))
+	suite.Len(res.Body.Directives, 0,
+		"Should not send the directive if there are more than 100 entities")
+	suite.Error(res.Err(),
+		"Should report why the directive wasn't sent")
+	suite.Error(golexa.ValidateDynamicEntities(golexa.NewDynamicSlotType("TodoItem", tooMany...)),
+		"Should fail validation if there are more than 100 entities")
+	suite.Error(golexa.ValidateDynamicEntities(golexa.NewDynamicSlotType("TodoItem", golexa.NewDynamicEntity("1", ""))),
+		"Should fail validation if an entity has no value")
+	suite.Error(golexa.ValidateDynamicEntities(golexa.NewDynamicSlotType("", golexa.NewDynamicEntity("1", "foo"))),
+		"Should fail validation if the slot type has no name")
+	suite.NoError(golexa.ValidateDynamicEntities(golexa.NewDynamicSlotType("TodoItem", tooMany[:100]...)),
+		"Should pass validation w/ exactly 100 entities")
+}
+
+func (suite ResponseSuite) TestClearDynamicEntities() {
+	res := golexa.NewResponse(golexa.Request{}).ClearDynamicEntities()
+	suite.Require().Len(res.Body.Directives, 1,
+		"Should add a directive to the response")
+
+	data, err := json.Marshal(res.Body.Directives[0])
+	suite.Require().NoError(err)
+	suite.JSONEq(`{"type": "Dialog.UpdateDynamicEntities", "updateBehavior": "CLEAR"}`, string(data),
+		"Should clear entities w/o including any types")
+}
@@ -0,0 +1,81 @@
+package golexa
+
+import "github.com/robsignorelli/golexa/model"
+
+// Router is anything that you can register handlers with. Both the Skill and its route groups
+// satisfy this, so packages that are developed independently of your main program can export a
+// function like `Register(router golexa.Router)` w/o caring which one they've been given.
+type Router interface {
+	RouteIntent(intentName string, handlerFunc HandlerFunc) *IntentRoute
+	CanFulfillIntent(handlerFunc HandlerFunc)
+	Launch(handlerFunc HandlerFunc)
+	OnSkillEvent(eventType string, handlerFunc HandlerFunc)
+	OnMessage(handlerFunc HandlerFunc)
+	SlotType(name string, values ...model.SlotTypeValue)
+	Group(middleware ...MiddlewareFunc) *RouteGroup
+}
+
+// RouteGroup lets you register a bunch of handlers that all share the same middleware (and optionally an
+// intent name prefix) w/o wrapping each of them manually. Everything you register is added directly to
+// the Skill that the group came from.
+//
+//	authorized := skill.Group(middleware.RequireAccount())
+//	authorized.RouteIntent("AddItem", addItem)
+//	authorized.RouteIntent("RemoveItem", removeItem)
+type RouteGroup struct {
+	skill      *Skill
+	prefix     string
+	middleware Middleware
+}
+
+// Group creates a nested group whose handlers run through this group's middleware first and then the
+// additional middleware you supply here. The prefix is inherited as well.
+func (group *RouteGroup) Group(middleware ...MiddlewareFunc) *RouteGroup {
+	return &RouteGroup{
+		skill:      group.skill,
+		prefix:     group.prefix,
+		middleware: append(append(Middleware{}, group.middleware...), middleware...),
+	}
+}
+
+// WithPrefix creates a nested group that prepends the given text to the name of every intent you route
+// through it. For instance, a "Todo_" group turns `RouteIntent("Add", ...)` into a route for "Todo_Add".
+// Keep in mind that Alexa only allows letters and underscores in intent names.
+func (group *RouteGroup) WithPrefix(prefix string) *RouteGroup {
+	return &RouteGroup{
+		skill:      group.skill,
+		prefix:     group.prefix + prefix,
+		middleware: group.middleware,
+	}
+}
+
+// RouteIntent registers the handler for the (prefixed) intent, wrapped in the group's middleware.
+func (group *RouteGroup) RouteIntent(intentName string, handlerFunc HandlerFunc) *IntentRoute {
+	return group.skill.RouteIntent(group.prefix+intentName, group.middleware.Then(handlerFunc))
+}
+
+// CanFulfillIntent registers the skill's CanFulfillIntentRequest handler, wrapped in the group's middleware.
+func (group *RouteGroup) CanFulfillIntent(handlerFunc HandlerFunc) {
+	group.skill.CanFulfillIntent(group.middleware.Then(handlerFunc))
+}
+
+// Launch registers the skill's LaunchRequest handler, wrapped in the group's middleware.
+func (group *RouteGroup) Launch(handlerFunc HandlerFunc) {
+	group.skill.Launch(group.middleware.Then(handlerFunc))
+}
+
+// OnSkillEvent registers the handler for the skill lifecycle event, wrapped in the group's middleware.
+func (group *RouteGroup) OnSkillEvent(eventType string, handlerFunc HandlerFunc) {
+	group.skill.OnSkillEvent(eventType, group.middleware.Then(handlerFunc))
+}
+
+// OnMessage registers the skill's "Messaging.MessageReceived" handler, wrapped in the group's middleware.
+func (group *RouteGroup) OnMessage(handlerFunc HandlerFunc) {
+	group.skill.OnMessage(group.middleware.Then(handlerFunc))
+}
+
+// SlotType declares one of your custom slot types on the group's skill. Unlike intents, slot type
+// names are not affected by the group's prefix.
+func (group *RouteGroup) SlotType(name string, values ...model.SlotTypeValue) {
+	group.skill.SlotType(name, values...)
+}
@@ -0,0 +1,101 @@
+package golexa_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/robsignorelli/golexa"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestRouteGroupSuite(t *testing.T) {
+	suite.Run(t, new(RouteGroupSuite))
+}
+
+type RouteGroupSuite struct {
+	suite.Suite
+}
+
+// trace creates a middleware function that records its label before moving on down the chain.
+func trace(calls *[]string, label string) golexa.MiddlewareFunc {
+	return func(ctx context.Context, request golexa.Request, next golexa.HandlerFunc) (golexa.Response, error) {
+		*calls = append(*calls, label)
+		return next(ctx, request)
+	}
+}
+
+func (suite RouteGroupSuite) handler(calls *[]string) golexa.HandlerFunc {
+	return func(ctx context.Context, request golexa.Request) (golexa.Response, error) {
+		*calls = append(*calls, "handler")
+		return golexa.NewResponse(request).Ok()
+	}
+}
+
+// register is how an independently developed package might add its own routes.
+func register(router golexa.Router, handler golexa.HandlerFunc) {
+	router.RouteIntent("Add", handler)
+}
+
+// Both the skill and its groups must be usable wherever a Router is expected.
+var (
+	_ golexa.Router = &golexa.Skill{}
+	_ golexa.Router = &golexa.RouteGroup{}
+)
+
+func (suite RouteGroupSuite) TestGroupMiddleware() {
+	var calls []string
+	skill := golexa.Skill{}
+	group := skill.Group(trace(&calls, "a"), trace(&calls, "b"))
+	nested := group.Group(trace(&calls, "c"))
+
+	skill.RouteIntent("Plain", suite.handler(&calls))
+	group.RouteIntent("Grouped", suite.handler(&calls))
+	nested.RouteIntent("Nested", suite.handler(&calls))
+	nested.Launch(suite.handler(&calls))
+
+	run := func(request golexa.Request) string {
+		calls = nil
+		_, err := skill.Handle(context.TODO(), request)
+		suite.Require().NoError(err, "Should not fail for registered routes")
+		return strings.Join(calls, ",")
+	}
+
+	suite.Equal("handler", run(golexa.NewIntentRequest("Plain", golexa.NewSlots())),
+		"Should not apply group middleware to routes registered on the skill")
+	suite.Equal("a,b,handler", run(golexa.NewIntentRequest("Grouped", golexa.NewSlots())),
+		"Should apply the group's middleware in order")
+	suite.Equal("a,b,c,handler", run(golexa.NewIntentRequest("Nested", golexa.NewSlots())),
+		"Should apply parent middleware before nested middleware")
+
+	launch := golexa.NewIntentRequest("", golexa.NewSlots())
+	launch.Body.Type = golexa.RequestTypeLaunch
+	suite.Equal("a,b,c,handler", run(launch),
+		"Should apply group middleware to non-intent handlers")
+
+	calls = nil
+	group.RouteIntent("Grouped", suite.handler(&calls))
+	_ = run(golexa.NewIntentRequest("Grouped", golexa.NewSlots()))
+	suite.Equal([]string{"a", "b", "handler"}, calls,
+		"Should not leak nested middleware back into the parent group")
+}
+
+func (suite RouteGroupSuite) TestGroupPrefix() {
+	var calls []string
+	skill := golexa.Skill{}
+	todo := skill.Group(trace(&calls, "todo")).WithPrefix("Todo_")
+	register(todo, suite.handler(&calls))
+	register(todo.WithPrefix("Bulk_").Group(trace(&calls, "bulk")), suite.handler(&calls))
+
+	_, err := skill.Handle(context.TODO(), golexa.NewIntentRequest("Add", golexa.NewSlots()))
+	suite.Error(err, "Should not register the un-prefixed intent")
+
+	_, err = skill.Handle(context.TODO(), golexa.NewIntentRequest("Todo_Add", golexa.NewSlots()))
+	suite.NoError(err, "Should register the prefixed intent")
+	suite.Equal([]string{"todo", "handler"}, calls, "Should apply the group middleware to prefixed intents")
+
+	calls = nil
+	_, err = skill.Handle(context.TODO(), golexa.NewIntentRequest("Todo_Bulk_Add", golexa.NewSlots()))
+	suite.NoError(err, "Should combine nested prefixes")
+	suite.Equal([]string{"todo", "bulk", "handler"}, calls, "Should keep middleware when adding prefixes")
+}
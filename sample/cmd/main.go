@@ -2,34 +2,48 @@ package main
 
 import (
 	"context"
+	"log"
 
 	"github.com/robsignorelli/golexa"
 	"github.com/robsignorelli/golexa/middleware"
+	"github.com/robsignorelli/golexa/model"
 	"github.com/robsignorelli/golexa/sample"
 	"github.com/robsignorelli/golexa/speech"
+	"golang.org/x/text/language"
 )
 
 func main() {
 	skill := golexa.Skill{}
-	registerSkillIntents(&skill)
+	skill.InvocationName(model.NewPhrases("to do list").WithTranslation(language.Spanish, "lista de tareas"))
+
+	// All of our handlers share one catalog of speech templates, so they can speak them by message ID.
+	messages := speech.NewCatalog()
+	skill.Catalog(messages)
+	skill.TemplateFallback(speech.NewTemplate("Hmm. Something went wrong. Try that again.",
+		speech.WithTranslation(language.Spanish, "Hmm. Algo salió mal. Inténtalo de nuevo.")))
+
+	registerSkillIntents(&skill, messages)
 	registerAmazonIntents(&skill)
+
+	// Refuse to start if we forgot to handle any of the intents Alexa requires.
+	if err := skill.Validate(sample.IntentAddTodoItem, sample.IntentRemoveTodoItem, sample.IntentListTodoItems); err != nil {
+		log.Fatal(err)
+	}
 	golexa.Start(skill)
 }
 
-func registerSkillIntents(skill *golexa.Skill) {
+func registerSkillIntents(skill *golexa.Skill, messages *speech.Catalog) {
 	// All of our list management intents should log the request and deny access to users
 	// that haven't gone through account linking.
-	mw := golexa.Middleware{
+	group := skill.Group(
 		middleware.Logger(
 			middleware.LogRequestJSON(),
 			middleware.LogResponseSpeech()),
 		middleware.RequireAccount(
-			middleware.RequireAccountTemplate(speech.NewTemplate("Link up your account, dude!"))),
-	}
-	todo := sample.NewTodoService(sample.NewTodoRepository())
-	skill.RouteIntent(sample.IntentAddTodoItem, mw.Then(todo.Add))
-	skill.RouteIntent(sample.IntentRemoveTodoItem, mw.Then(todo.Remove))
-	skill.RouteIntent(sample.IntentListTodoItems, mw.Then(todo.List))
+			middleware.RequireAccountTemplate(speech.NewTemplate("Link up your account, dude!"))))
+
+	todo := sample.NewTodoService(sample.NewTodoRepository(), messages)
+	todo.Register(group)
 }
 
 func registerAmazonIntents(skill *golexa.Skill) {
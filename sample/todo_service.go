@@ -2,9 +2,9 @@ package sample
 
 import (
 	"context"
-	"strings"
 
 	"github.com/robsignorelli/golexa"
+	"github.com/robsignorelli/golexa/model"
 	"github.com/robsignorelli/golexa/speech"
 	"golang.org/x/text/language"
 )
@@ -12,6 +12,10 @@ import (
 // SlotItemName is the name of the slot where users specify items to add/remove.
 const SlotItemName = "item_name"
 
+// SlotTypeTodoItem is the custom slot type for the {item_name} slot. We push each user's own items to
+// Alexa as dynamic entities so that "remove laundry" resolves to the item they actually added.
+const SlotTypeTodoItem = "TodoItem"
+
 // IntentAddTodoItem is the name of the intent where we add new items to the user's list
 const IntentAddTodoItem = "AddTodoItem"
 
@@ -21,65 +25,135 @@ const IntentRemoveTodoItem = "RemoveTodoItem"
 // IntentListTodoItems is the name of the intent where we have Alexa rattle off all of a user's items
 const IntentListTodoItems = "ListTodoItems"
 
+// The IDs of all of the messages that the to-do service speaks.
+const (
+	MessageLaunch         = "todo.launch"
+	MessageAddElicit      = "todo.add.elicit"
+	MessageAddSuccess     = "todo.add.success"
+	MessageRemoveElicit   = "todo.remove.elicit"
+	MessageRemoveNotFound = "todo.remove.notFound"
+	MessageRemoveSuccess  = "todo.remove.success"
+	MessageListEmpty      = "todo.list.empty"
+	MessageListSuccess    = "todo.list.success"
+)
+
 // NewTodoService creates a controller/service that handles all of the intents related to managing
-// your items list.
-func NewTodoService(repository TodoRepository) TodoService {
-	service := TodoService{repository: repository}
+// your items list. It registers all of the speech templates it needs in the skill's message catalog.
+func NewTodoService(repository TodoRepository, messages *speech.Catalog) TodoService {
+	service := TodoService{repository: repository, messages: messages}
+
+	// When the user opens the skill w/o asking for anything in particular.
+	messages.Add(MessageLaunch, speech.NewTemplate(
+		`Welcome to your to-do list. What would you like to do?`,
+		speech.WithVariants(language.AmericanEnglish, `Welcome back. What can I do for your list?`),
+		speech.WithTranslation(language.Spanish, `Bienvenido a tu lista de tareas. ¿Qué te gustaría hacer?`),
+		speech.WithSelection(speech.NeverRepeatLast)))
 
 	// When you hit the "AddTodoItem" intent but didn't specify an item name.
-	service.templateAddElicit = speech.NewTemplate(
+	messages.Add(MessageAddElicit, speech.NewTemplate(
 		`What would you like to add to your list?`,
-		speech.WithTranslation(language.Spanish, `¿Qué te gustaría agregar a tu lista?`))
+		speech.WithTranslation(language.Spanish, `¿Qué te gustaría agregar a tu lista?`)))
 
 	// The success confirmation for the "AddTodoItem" intent
-	service.templateAddSuccess = speech.NewTemplate(
+	messages.Add(MessageAddSuccess, speech.NewTemplate(
 		`Okay. I have added "{{.Value}}" to your list.`,
-		speech.WithFunc("normalize", strings.ToUpper),
-		speech.WithTranslation(language.Spanish, `Bueno. He agregado "{{.Value | normalize}}" a su lista.`))
+		speech.WithTranslation(language.Spanish, `Bueno. He agregado "{{upper .Value}}" a su lista.`)))
 
 	// When you hit the "RemoveTodoItem" intent but didn't specify an item name.
-	service.templateRemoveElicit = speech.NewTemplate(
+	messages.Add(MessageRemoveElicit, speech.NewTemplate(
 		`What would you like to remove from your list?`,
-		speech.WithTranslation(language.Spanish, `¿Qué te gustaría eliminar de tu lista?`))
+		speech.WithTranslation(language.Spanish, `¿Qué te gustaría eliminar de tu lista?`)))
 
 	// When you hit the "RemoveTodoItem" intent but we couldn't find an item w/ that name.
-	service.templateRemoveNotFound = speech.NewTemplate(
+	messages.Add(MessageRemoveNotFound, speech.NewTemplate(
 		`I'm sorry. I couldn't find "{{.Value}}" in the list.`,
-		speech.WithTranslation(language.Spanish, `Lo siento. No pude encontrar "{{.Value}}" en la lista.`))
+		speech.WithTranslation(language.Spanish, `Lo siento. No pude encontrar "{{.Value}}" en la lista.`)))
 
 	// The success confirmation for the "RemoveTodoItem" intent
-	service.templateRemoveSuccess = speech.NewTemplate(
+	messages.Add(MessageRemoveSuccess, speech.NewTemplate(
 		`Okay. I have removed "{{.Value}}" from your list.`,
-		speech.WithFunc("normalize", strings.ToUpper),
-		speech.WithTranslation(language.Spanish, `Bueno. He eliminado "{{.Value | normalize}}" de su lista.`))
+		speech.WithTranslation(language.Spanish, `Bueno. He eliminado "{{upper .Value}}" de su lista.`)))
 
 	// When you hit the "ListTodoItems" intent, but don't have any items in the list.
-	service.templateListEmpty = speech.NewTemplate(
+	messages.Add(MessageListEmpty, speech.NewTemplate(
 		`Hmm. Your list is empty.`,
-		speech.WithTranslation(language.Spanish, `Tu lista esta vacia.`))
+		speech.WithTranslation(language.Spanish, `Tu lista esta vacia.`)))
 
 	// Confirmation speech for when you hit the "ListTodoItems".
-	service.templateListSuccess = speech.NewTemplate(
-		`I found {{len .Value}} items in your list. {{range .Value}}{{. | normalize}}. {{end}}`,
-		speech.WithFunc("normalize", strings.ToUpper),
+	messages.Add(MessageListSuccess, speech.NewTemplate(
+		`I found {{plural (len .Value) "one" "# item" "other" "# items"}} in your list: {{list .Value}}.`,
 		speech.WithTranslation(language.Spanish,
-			`Encontré {{len .Value}} artículos en tu lista. {{range .Value}}{{. | normalize}}. {{end}}`))
+			`Encontré {{plural (len .Value) "one" "# artículo" "other" "# artículos"}} en tu lista: {{list .Value}}.`)))
 
 	return service
 }
 
 // TodoService wrangles all of of the dependencies for our list management business logic as well as our
-// handlers and response templates for the various interactions we support.
+// handlers and the catalog w/ the response templates for the various interactions we support.
 type TodoService struct {
 	repository TodoRepository
+	messages   *speech.Catalog
+}
+
+// Register adds all of the to-do list handlers to the router. Your main program decides which
+// middleware these run through by handing us a route group rather than the skill itself. We also
+// declare what users can say to trigger each intent, so `golexa model export` can build our model.
+func (service *TodoService) Register(router golexa.Router) {
+	itemSlot := func(elicitMessage string) model.Slot {
+		elicit, _ := service.messages.Template(elicitMessage)
+		return model.Slot{
+			Name:        SlotItemName,
+			Type:        SlotTypeTodoItem,
+			Samples:     model.NewPhrases("{item_name}"),
+			Elicitation: &elicit,
+		}
+	}
+
+	// Alexa requires at least one value for custom slot types. The real values for each user are
+	// sent as dynamic entities when they launch the skill.
+	router.SlotType(SlotTypeTodoItem,
+		model.NewSlotTypeValue("laundry", "laundry", "wash clothes").WithTranslation(language.Spanish, "lavar la ropa"))
+
+	router.Launch(service.Launch)
+	router.RouteIntent(IntentAddTodoItem, service.Add).
+		Samples(model.NewPhrases("add {item_name} to my list", "add {item_name}", "update my list").
+			WithTranslation(language.Spanish, "agrega {item_name} a mi lista", "actualiza mi lista")).
+		Slot(itemSlot(MessageAddElicit))
+	router.RouteIntent(IntentRemoveTodoItem, service.Remove).
+		Samples(model.NewPhrases("remove {item_name} from my list", "remove {item_name}", "delete {item_name}").
+			WithTranslation(language.Spanish, "elimina {item_name} de mi lista")).
+		Slot(itemSlot(MessageRemoveElicit))
+	router.RouteIntent(IntentListTodoItems, service.List).
+		Samples(model.NewPhrases("what is on my list", "read my list", "list my items").
+			WithTranslation(language.Spanish, "qué hay en mi lista", "lee mi lista"))
+}
+
+// Launch greets the user when they open the skill and sends Alexa the items already in their list as
+// dynamic entities. That way, a follow-up like "remove laundry" resolves to the exact item they added
+// even if they phrase it slightly differently.
+func (service *TodoService) Launch(_ context.Context, request golexa.Request) (golexa.Response, error) {
+	response := golexa.NewResponse(request).
+		SpeakMessage(MessageLaunch, nil).
+		EndSession(false)
+
+	items := service.repository.GetItems(request.Session.User.ID)
+	if len(items) == 0 {
+		return response.Ok()
+	}
 
-	templateAddElicit      speech.Template
-	templateAddSuccess     speech.Template
-	templateRemoveElicit   speech.Template
-	templateRemoveSuccess  speech.Template
-	templateRemoveNotFound speech.Template
-	templateListEmpty      speech.Template
-	templateListSuccess    speech.Template
+	// Alexa only accepts 100 dynamic entities, so just send the first 100 items in huge lists.
+	var entities []golexa.DynamicEntity
+	for i := 0; i < len(items) && i < 100; i++ {
+		entities = append(entities, golexa.NewDynamicEntity(items[i], items[i]))
+	}
+	return response.
+		UpdateDynamicEntities(golexa.NewDynamicSlotType(SlotTypeTodoItem, entities...)).
+		Ok()
+}
+
+// itemSlots are the slots that both the "AddTodoItem" and "RemoveTodoItem" intents expect.
+type itemSlots struct {
+	ItemName string `slot:"item_name,required"`
 }
 
 // Add appends the item that the user uttered to their personal to-do list. It responds to an
@@ -90,20 +164,24 @@ type TodoService struct {
 func (service *TodoService) Add(_ context.Context, request golexa.Request) (golexa.Response, error) {
 	// Not a failure. Have Alexa prompt the user for what the items should be. Once the user
 	// responds, this intent should be re-invoked, but this time with the name filled in.
-	itemName := request.Body.Intent.Slots.Resolve(SlotItemName)
-	if itemName == "" {
-		return golexa.NewResponse(request).
-			SpeakTemplate(service.templateAddElicit, nil).
-			ElicitSlot(IntentAddTodoItem, SlotItemName).
-			Ok()
+	var slots itemSlots
+	if err := request.BindSlots(&slots); err != nil {
+		if missing := golexa.MissingSlots(err); len(missing) > 0 {
+			return golexa.NewResponse(request).
+				SpeakMessage(MessageAddElicit, nil).
+				ElicitSlot(IntentAddTodoItem, missing[0]).
+				Ok()
+		}
+		return golexa.Fail(err.Error())
 	}
+	itemName := slots.ItemName
 
 	// Do your "business logic" to handle the user's request.
 	service.repository.AddItem(request.Session.User.ID, itemName)
 
 	// Have Alexa speak some sort of confirmation.
 	return golexa.NewResponse(request).
-		SpeakTemplate(service.templateAddSuccess, itemName).
+		SpeakMessage(MessageAddSuccess, itemName).
 		Ok()
 }
 
@@ -112,24 +190,28 @@ func (service *TodoService) Add(_ context.Context, request golexa.Request) (gole
 func (service *TodoService) Remove(_ context.Context, request golexa.Request) (golexa.Response, error) {
 	// Not a failure. Have Alexa prompt the user for what the items should be. Once the user
 	// responds, this intent should be re-invoked, but this time with the name filled in.
-	itemName := request.Body.Intent.Slots.Resolve(SlotItemName)
-	if itemName == "" {
-		return golexa.NewResponse(request).
-			SpeakTemplate(service.templateRemoveElicit, nil).
-			ElicitSlot(IntentAddTodoItem, SlotItemName).
-			Ok()
+	var slots itemSlots
+	if err := request.BindSlots(&slots); err != nil {
+		if missing := golexa.MissingSlots(err); len(missing) > 0 {
+			return golexa.NewResponse(request).
+				SpeakMessage(MessageRemoveElicit, nil).
+				ElicitSlot(IntentRemoveTodoItem, missing[0]).
+				Ok()
+		}
+		return golexa.Fail(err.Error())
 	}
+	itemName := slots.ItemName
 
 	// Do your "business logic" to handle the user's request.
 	if err := service.repository.RemoveItem(request.Session.User.ID, itemName); err == ErrItemNotFound {
 		return golexa.NewResponse(request).
-			SpeakTemplate(service.templateRemoveNotFound, itemName).
+			SpeakMessage(MessageRemoveNotFound, itemName).
 			Ok()
 	}
 
 	// Have Alexa speak some sort of confirmation.
 	return golexa.NewResponse(request).
-		SpeakTemplate(service.templateRemoveSuccess, itemName).
+		SpeakMessage(MessageRemoveSuccess, itemName).
 		Ok()
 }
 
@@ -139,11 +221,11 @@ func (service *TodoService) List(_ context.Context, request golexa.Request) (gol
 	items := service.repository.GetItems(request.Session.User.ID)
 	if len(items) == 0 {
 		return golexa.NewResponse(request).
-			SpeakTemplate(service.templateListEmpty, nil).
+			SpeakMessage(MessageListEmpty, nil).
 			Ok()
 	}
 
 	return golexa.NewResponse(request).
-		SpeakTemplate(service.templateListSuccess, items).
+		SpeakMessage(MessageListSuccess, items).
 		Ok()
 }
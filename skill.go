@@ -2,6 +2,10 @@ package golexa
 
 import (
 	"context"
+	"sort"
+
+	"github.com/robsignorelli/golexa/model"
+	"github.com/robsignorelli/golexa/speech"
 )
 
 // HandlerFunc defines a core operation of your skill. It takes the request with all incoming
@@ -22,21 +26,38 @@ const (
 // different types of requests your skill is expected to encounter.
 type Skill struct {
 	Name       string
-	intents    map[string]intentRoute
+	intents    map[string][]*IntentRoute
 	canFulfill HandlerFunc
 	launch     HandlerFunc
+	events     map[string]HandlerFunc
+	message    HandlerFunc
+
+	invocationName model.Phrases
+	slotTypes      []model.SlotType
+	templates      templateSettings
+}
+
+// templateSettings are the skill-wide settings that control how responses evaluate your templates.
+type templateSettings struct {
+	catalog  *speech.Catalog
+	strict   bool
+	fallback *speech.Template
 }
 
 // RouteIntent indicates that any "IntentRequest" with the specified intent name should be handled
-// by the given function.
-func (skill *Skill) RouteIntent(intentName string, handlerFunc HandlerFunc) {
+// by the given function. You can register multiple routes for the same intent, using conditions
+// like `WhenSlot()` on the returned route to decide which one handles a given request. If you register
+// more than one route w/o any conditions, the last one wins.
+func (skill *Skill) RouteIntent(intentName string, handlerFunc HandlerFunc) *IntentRoute {
 	if skill.intents == nil {
-		skill.intents = map[string]intentRoute{}
+		skill.intents = map[string][]*IntentRoute{}
 	}
-	skill.intents[intentName] = intentRoute{
+	route := &IntentRoute{
 		name:        intentName,
 		handlerFunc: handlerFunc,
 	}
+	skill.intents[intentName] = append(skill.intents[intentName], route)
+	return route
 }
 
 // CanFulfillIntent allows you to support the "pre-flight" CanFulfillIntentRequest if you want to
@@ -52,8 +73,100 @@ func (skill *Skill) Launch(handlerFunc HandlerFunc) {
 	skill.launch = handlerFunc
 }
 
+// OnSkillEvent registers the handler for one of the skill lifecycle events such as when the user
+// enables/disables your skill or links their account (e.g. RequestTypeSkillDisabled). Alexa does not
+// speak anything in response to these events, so your handler can simply return `NewResponse(request).Ok()`
+// once it has done its work.
+func (skill *Skill) OnSkillEvent(eventType string, handlerFunc HandlerFunc) {
+	if skill.events == nil {
+		skill.events = map[string]HandlerFunc{}
+	}
+	skill.events[eventType] = handlerFunc
+}
+
+// OnMessage registers the handler for "Messaging.MessageReceived" requests. These are the messages
+// that your background jobs send to your skill using the Skill Messaging API (see the `messaging` package).
+// Use `Request.DecodeMessage()` to get at the data that was sent.
+func (skill *Skill) OnMessage(handlerFunc HandlerFunc) {
+	skill.message = handlerFunc
+}
+
+// InvocationName declares what users say to open your skill (e.g. "Alexa, open to do list") in your
+// interaction model (see `Skill.Model()`). Use `WithTranslation()` to give it a name in other languages.
+func (skill *Skill) InvocationName(names model.Phrases) {
+	skill.invocationName = names
+}
+
+// SlotType declares one of your custom slot types and all of its values in your interaction model
+// (see `Skill.Model()`). Declaring the same type again replaces the original.
+func (skill *Skill) SlotType(name string, values ...model.SlotTypeValue) {
+	slotType := model.SlotType{Name: name, Values: values}
+	for i := range skill.slotTypes {
+		if skill.slotTypes[i].Name == name {
+			skill.slotTypes[i] = slotType
+			return
+		}
+	}
+	skill.slotTypes = append(skill.slotTypes, slotType)
+}
+
+// Model assembles the interaction model from everything you declared alongside your routes. Every routed
+// intent is included (even the built-ins that don't need samples), and multiple routes for the same intent
+// combine their samples and slots. Use `Model().Export()` or the "golexa model export" command to generate
+// the JSON that you give to the ASK CLI so your code and your model never drift apart.
+func (skill Skill) Model() model.Model {
+	m := model.Model{
+		InvocationName: skill.invocationName,
+		SlotTypes:      skill.slotTypes,
+	}
+	for intentName, routes := range skill.intents {
+		intent := model.Intent{Name: intentName}
+		for _, route := range routes {
+			intent = intent.Merge(route.definition)
+		}
+		m.Intents = append(m.Intents, intent)
+	}
+	sort.Slice(m.Intents, func(i, j int) bool {
+		return m.Intents[i].Name < m.Intents[j].Name
+	})
+	return m
+}
+
+// Catalog sets the templates that your handlers can speak by message ID using `Response.SpeakMessage()`
+// and `Response.RepromptMessage()`, so you don't need to pass templates around to all of your handlers.
+func (skill *Skill) Catalog(catalog *speech.Catalog) {
+	skill.templates.catalog = catalog
+}
+
+// StrictTemplates controls what happens when one of your templates or messages fails to evaluate. By
+// default, we have Alexa speak your fallback (see `TemplateFallback()`) so the user at least hears
+// something. In strict mode, `Response.Ok()` returns the template error instead, so your handler (and
+// your tests) can't miss it. Either way, `Response.Err()` tells you what went wrong; golexa doesn't log
+// it for you, but the `middleware.Logger()` does.
+func (skill *Skill) StrictTemplates(strict bool) {
+	skill.templates.strict = strict
+}
+
+// TemplateFallback sets what Alexa speaks when one of your templates or messages fails to evaluate
+// instead of our canned apology. The fallback is evaluated w/ a nil value, but it still has access
+// to the request, session, and data, so it can be localized like any other template.
+func (skill *Skill) TemplateFallback(template speech.Template) {
+	skill.templates.fallback = &template
+}
+
+// Group creates a set of routes that all run through the given middleware before their handlers. You
+// can nest groups to layer on additional middleware for a subset of your routes.
+func (skill *Skill) Group(middleware ...MiddlewareFunc) *RouteGroup {
+	return &RouteGroup{
+		skill:      skill,
+		middleware: middleware,
+	}
+}
+
 // Handle routes the incoming Alexa request to the correct, registered handler.
 func (skill Skill) Handle(ctx context.Context, request Request) (Response, error) {
+	request.templates = skill.templates
+
 	switch request.Body.Type {
 	case RequestTypeIntent:
 		return skill.handleIntent(ctx, request)
@@ -61,6 +174,14 @@ func (skill Skill) Handle(ctx context.Context, request Request) (Response, error
 		return skill.handleCanFulfillIntent(ctx, request)
 	case RequestTypeLaunch:
 		return skill.handleLaunch(ctx, request)
+	case RequestTypeSkillEnabled,
+		RequestTypeSkillDisabled,
+		RequestTypeSkillPermissionAccepted,
+		RequestTypeSkillPermissionChanged,
+		RequestTypeSkillAccountLinked:
+		return skill.handleSkillEvent(ctx, request)
+	case RequestTypeMessageReceived:
+		return skill.handleMessage(ctx, request)
 	default:
 		return Fail("golexa: unsupported request type: " + request.Body.Type)
 	}
@@ -72,11 +193,26 @@ func (skill Skill) handleIntent(ctx context.Context, request Request) (Response,
 	}
 
 	name := request.Body.Intent.Name
-	intentRoute, ok := skill.intents[name]
-	if !ok {
+	route := skill.matchIntent(ctx, request)
+	if route == nil {
 		return Fail("golexa: no handler registered for intent: " + name)
 	}
-	return intentRoute.handlerFunc(ctx, request)
+	return route.handlerFunc(ctx, request)
+}
+
+// matchIntent finds the first conditional route for the request's intent whose conditions are all
+// satisfied, falling back to the plain route for the intent if none of them match.
+func (skill Skill) matchIntent(ctx context.Context, request Request) *IntentRoute {
+	var fallback *IntentRoute
+	for _, route := range skill.intents[request.Body.Intent.Name] {
+		switch {
+		case !route.conditional():
+			fallback = route
+		case route.matches(ctx, request):
+			return route
+		}
+	}
+	return fallback
 }
 
 func (skill Skill) handleCanFulfillIntent(ctx context.Context, request Request) (Response, error) {
@@ -93,7 +229,18 @@ func (skill Skill) handleLaunch(ctx context.Context, request Request) (Response,
 	return skill.launch(ctx, request)
 }
 
-type intentRoute struct {
-	handlerFunc HandlerFunc
-	name        string
+func (skill Skill) handleSkillEvent(ctx context.Context, request Request) (Response, error) {
+	eventType := request.Body.Type
+	handlerFunc, ok := skill.events[eventType]
+	if !ok {
+		return Fail("golexa: no handler registered for skill event: " + eventType)
+	}
+	return handlerFunc(ctx, request)
+}
+
+func (skill Skill) handleMessage(ctx context.Context, request Request) (Response, error) {
+	if skill.message == nil {
+		return Fail("golexa: no handler registered for Messaging.MessageReceived")
+	}
+	return skill.message(ctx, request)
 }
@@ -0,0 +1,187 @@
+package golexa
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// BuiltInIntents are the standard Amazon intents that every custom skill must handle in order to
+// pass certification. `Skill.Validate()` makes sure you have routes for all of them.
+var BuiltInIntents = []string{
+	IntentNameCancel,
+	IntentNameFallback,
+	IntentNameHelp,
+	IntentNameNavigateHome,
+	IntentNameStop,
+}
+
+// RouteInfo describes one of the handlers registered with your skill.
+type RouteInfo struct {
+	// RequestType is the type of request that the handler responds to (e.g. RequestTypeIntent). Skill
+	// events are their own request types, so this is the event type (e.g. RequestTypeSkillDisabled).
+	RequestType string
+	// Name is the intent name for intent routes. It's blank for every other request type, including
+	// skill events, since they only have a single handler per request type.
+	Name string
+	// Conditional is true for intent routes that only handle some requests based on conditions
+	// such as `WhenSlot()`.
+	Conditional bool
+}
+
+// Routes returns all of the handlers registered with your skill, sorted by request type and name. Intents
+// with multiple routes appear once per route, in the order they are evaluated.
+func (skill Skill) Routes() []RouteInfo {
+	var routes []RouteInfo
+	for _, intentRoutes := range skill.intents {
+		for _, route := range intentRoutes {
+			routes = append(routes, RouteInfo{RequestType: RequestTypeIntent, Name: route.name, Conditional: route.conditional()})
+		}
+	}
+	for eventType := range skill.events {
+		routes = append(routes, RouteInfo{RequestType: eventType})
+	}
+	if skill.canFulfill != nil {
+		routes = append(routes, RouteInfo{RequestType: RequestTypeCanFulfillIntent})
+	}
+	if skill.launch != nil {
+		routes = append(routes, RouteInfo{RequestType: RequestTypeLaunch})
+	}
+	if skill.message != nil {
+		routes = append(routes, RouteInfo{RequestType: RequestTypeMessageReceived})
+	}
+
+	// Stable so that multiple routes for the same intent stay in evaluation order.
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].RequestType != routes[j].RequestType {
+			return routes[i].RequestType < routes[j].RequestType
+		}
+		return routes[i].Name < routes[j].Name
+	})
+	return routes
+}
+
+// RouteValidationError describes everything that `Skill.Validate()` found wrong w/ your routes.
+type RouteValidationError struct {
+	// MissingIntents are the intents that your skill must handle, but has no routes for.
+	MissingIntents []string
+	// ConditionalIntents are the intents that your skill must handle, but only have conditional routes
+	// (e.g. `WhenSlot()`), so requests that don't match any of the conditions have nowhere to go.
+	ConditionalIntents []string
+	// UnknownIntents are the intents you registered routes for that aren't in your interaction model.
+	UnknownIntents []string
+}
+
+func (err RouteValidationError) Error() string {
+	var problems []string
+	if len(err.MissingIntents) > 0 {
+		problems = append(problems, "missing routes for intents: "+strings.Join(err.MissingIntents, ", "))
+	}
+	if len(err.ConditionalIntents) > 0 {
+		problems = append(problems, "only conditional routes for intents: "+strings.Join(err.ConditionalIntents, ", "))
+	}
+	if len(err.UnknownIntents) > 0 {
+		problems = append(problems, "routes for intents not in the model: "+strings.Join(err.UnknownIntents, ", "))
+	}
+	return "golexa: " + strings.Join(problems, "; ")
+}
+
+// Validate makes sure that your skill has a route for each of the BuiltInIntents as well as any
+// additional intents you supply. Call this before `Start()` so that you find out about a forgotten
+// "AMAZON.StopIntent" when you deploy rather than when a user tries to leave your skill. An intent
+// only counts as handled if it has a route w/o any conditions to catch the requests that don't match
+// your conditional ones. The error is a RouteValidationError when any intents are missing.
+func (skill Skill) Validate(requiredIntents ...string) error {
+	missing, conditional := skill.missingIntents(append(append([]string{}, BuiltInIntents...), requiredIntents...))
+	if len(missing) > 0 || len(conditional) > 0 {
+		return RouteValidationError{MissingIntents: missing, ConditionalIntents: conditional}
+	}
+	return nil
+}
+
+// ValidateModel checks your routes against the interaction model JSON that you use in the Alexa developer
+// console. Just like `Validate()`, you must handle all of the BuiltInIntents, but you must also have a route
+// for every intent in the model and may not have routes for intents that aren't in the model.
+func (skill Skill) ValidateModel(model io.Reader) error {
+	modelIntents, err := readModelIntents(model)
+	if err != nil {
+		return err
+	}
+
+	missing, conditional := skill.missingIntents(append(append([]string{}, BuiltInIntents...), modelIntents...))
+	var unknown []string
+	for intentName := range skill.intents {
+		if !containsString(modelIntents, intentName) {
+			unknown = append(unknown, intentName)
+		}
+	}
+	sort.Strings(unknown)
+
+	if len(missing) > 0 || len(conditional) > 0 || len(unknown) > 0 {
+		return RouteValidationError{MissingIntents: missing, ConditionalIntents: conditional, UnknownIntents: unknown}
+	}
+	return nil
+}
+
+// missingIntents returns the sorted, de-duplicated intent names that don't have any routes as well as
+// the ones that only have conditional routes.
+func (skill Skill) missingIntents(intentNames []string) ([]string, []string) {
+	var missing, conditional []string
+	for _, intentName := range intentNames {
+		routes := skill.intents[intentName]
+		switch {
+		case containsString(missing, intentName) || containsString(conditional, intentName):
+			continue
+		case len(routes) == 0:
+			missing = append(missing, intentName)
+		case !hasUnconditionalRoute(routes):
+			conditional = append(conditional, intentName)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(conditional)
+	return missing, conditional
+}
+
+// hasUnconditionalRoute returns true if any of the routes handles requests regardless of conditions.
+func hasUnconditionalRoute(routes []*IntentRoute) bool {
+	for _, route := range routes {
+		if !route.conditional() {
+			return true
+		}
+	}
+	return false
+}
+
+// readModelIntents extracts the names of all of the intents in an interaction model's JSON.
+func readModelIntents(model io.Reader) ([]string, error) {
+	var doc struct {
+		InteractionModel struct {
+			LanguageModel struct {
+				Intents []struct {
+					Name string `json:"name"`
+				} `json:"intents"`
+			} `json:"languageModel"`
+		} `json:"interactionModel"`
+	}
+	if err := json.NewDecoder(model).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("golexa: unable to read interaction model: %v", err)
+	}
+
+	var intentNames []string
+	for _, intent := range doc.InteractionModel.LanguageModel.Intents {
+		intentNames = append(intentNames, intent.Name)
+	}
+	return intentNames, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
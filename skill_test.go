@@ -1,11 +1,18 @@
 package golexa_test
 
 import (
+	"bytes"
 	"context"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/robsignorelli/golexa"
+	"github.com/robsignorelli/golexa/model"
+	"github.com/robsignorelli/golexa/speech"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/suite"
+	"golang.org/x/text/language"
 )
 
 func TestSkillSuite(t *testing.T) {
@@ -48,3 +55,334 @@ func (suite SkillSuite) TestStandardRouteTable() {
 	suite.Equal("<speak>Handler 2</speak>", res.Body.OutputSpeech.SSML,
 		"Should execute the appropriate handler for valid intent names")
 }
+
+func (suite SkillSuite) TestSkillEvents() {
+	var disabledUserID string
+
+	skill := golexa.Skill{}
+	skill.OnSkillEvent(golexa.RequestTypeSkillDisabled, func(ctx context.Context, request golexa.Request) (golexa.Response, error) {
+		disabledUserID = request.UserID()
+		return golexa.NewResponse(request).Ok()
+	})
+
+	_, err := skill.Handle(context.TODO(), golexa.NewSkillEventRequest(golexa.RequestTypeSkillEnabled, "user.123"))
+	suite.Error(err, "Should result in an error when there's no handler for the event type")
+
+	_, err = skill.Handle(context.TODO(), golexa.NewSkillEventRequest(golexa.RequestTypeSkillDisabled, "user.123"))
+	suite.NoError(err, "Should not generate error for registered skill events")
+	suite.Equal("user.123", disabledUserID,
+		"Should execute the appropriate handler for the event type")
+}
+
+func (suite SkillSuite) TestMessages() {
+	type refresh struct {
+		Items []string `json:"items"`
+	}
+
+	skill := golexa.Skill{}
+	_, err := skill.Handle(context.TODO(), golexa.NewMessageRequest("user.123", refresh{}))
+	suite.Error(err, "Should result in an error when there's no message handler")
+
+	var received refresh
+	skill.OnMessage(func(ctx context.Context, request golexa.Request) (golexa.Response, error) {
+		if err := request.DecodeMessage(&received); err != nil {
+			return golexa.Fail(err.Error())
+		}
+		return golexa.NewResponse(request).Ok()
+	})
+
+	_, err = skill.Handle(context.TODO(), golexa.NewMessageRequest("user.123", refresh{Items: []string{"eggs"}}))
+	suite.NoError(err, "Should not generate error when there's a message handler")
+	suite.Equal([]string{"eggs"}, received.Items,
+		"Should be able to decode the message that was sent")
+
+	err = golexa.NewIntentRequest("Foo", golexa.NewSlots()).DecodeMessage(&received)
+	suite.Error(err, "Should not be able to decode a message from non-message requests")
+}
+
+func (suite SkillSuite) TestConditionalRoutes() {
+	speak := func(text string) golexa.HandlerFunc {
+		return func(ctx context.Context, request golexa.Request) (golexa.Response, error) {
+			return golexa.NewResponse(request).Speak(text).Ok()
+		}
+	}
+	run := func(skill golexa.Skill, request golexa.Request) string {
+		res, err := skill.Handle(context.TODO(), request)
+		suite.Require().NoError(err, "Should not generate error for valid routes")
+		return res.Body.OutputSpeech.SSML
+	}
+
+	skill := golexa.Skill{}
+	skill.RouteIntent("Play", speak("Podcast")).WhenSlot("media", "podcast")
+	skill.RouteIntent("Play", speak("Music")).WhenSlot("media", "music", "song")
+	skill.RouteIntent("Play", speak("Done")).WhenDialogState(golexa.DialogStateCompleted)
+	skill.RouteIntent("Play", speak("Paused")).WhenSession(func(session golexa.Session) bool {
+		return session.Attributes["paused"] == true
+	})
+	skill.RouteIntent("Play", speak("Fallback"))
+
+	_, err := golexa.Skill{}.Handle(context.TODO(), golexa.NewIntentRequest("Play", golexa.NewSlots()))
+	suite.Error(err, "Should fail when there are no routes for the intent")
+
+	suite.Equal("<speak>Podcast</speak>", run(skill, golexa.NewIntentRequest("Play", golexa.NewSlots(
+		golexa.NewSlot("media", "PODCAST")))),
+		"Should route based on slot values, ignoring case")
+	suite.Equal("<speak>Music</speak>", run(skill, golexa.NewIntentRequest("Play", golexa.NewSlots(
+		golexa.NewListSlot("media", "rock", "music")))),
+		"Should route when any value of a multi-value slot matches")
+	suite.Equal("<speak>Fallback</speak>", run(skill, golexa.NewIntentRequest("Play", golexa.NewSlots(
+		golexa.NewSlot("media", "audiobook")))),
+		"Should use the plain route when no conditions match")
+
+	request := golexa.NewIntentRequest("Play", golexa.NewSlots(golexa.NewSlot("media", "podcast")))
+	request.Body.DialogState = golexa.DialogStateCompleted
+	suite.Equal("<speak>Podcast</speak>", run(skill, request),
+		"Should evaluate routes in registration order")
+
+	request = golexa.NewIntentRequest("Play", golexa.NewSlots())
+	request.Body.DialogState = golexa.DialogStateCompleted
+	suite.Equal("<speak>Done</speak>", run(skill, request),
+		"Should route based on dialog state")
+
+	request = golexa.NewIntentRequest("Play", golexa.NewSlots())
+	request.Session.Attributes = map[string]interface{}{"paused": true}
+	suite.Equal("<speak>Paused</speak>", run(skill, request),
+		"Should route based on the session")
+
+	skill = golexa.Skill{}
+	skill.RouteIntent("Play", speak("Any")).WhenSlot("media")
+	skill.RouteIntent("Play", speak("Both")).
+		When(func(ctx context.Context, request golexa.Request) bool { return true }).
+		When(func(ctx context.Context, request golexa.Request) bool { return false })
+	_, err = skill.Handle(context.TODO(), golexa.NewIntentRequest("Play", golexa.NewSlots()))
+	suite.Error(err, "Should fail when no conditions match and there's no plain route")
+	suite.Equal("<speak>Any</speak>", run(skill, golexa.NewIntentRequest("Play", golexa.NewSlots(
+		golexa.NewSlot("media", "anything")))),
+		"Should match any value when the slot condition has no values")
+}
+
+func (suite SkillSuite) TestRoutes() {
+	noop := func(ctx context.Context, request golexa.Request) (golexa.Response, error) {
+		return golexa.NewResponse(request).Ok()
+	}
+
+	skill := golexa.Skill{}
+	suite.Empty(skill.Routes(), "Should not have any routes for an empty skill")
+
+	skill.RouteIntent("Play", noop).WhenSlot("media", "music")
+	skill.RouteIntent("Play", noop)
+	skill.RouteIntent("Add", noop)
+	skill.Launch(noop)
+	skill.OnSkillEvent(golexa.RequestTypeSkillDisabled, noop)
+	skill.OnMessage(noop)
+
+	suite.Equal([]golexa.RouteInfo{
+		{RequestType: golexa.RequestTypeSkillDisabled},
+		{RequestType: golexa.RequestTypeIntent, Name: "Add"},
+		{RequestType: golexa.RequestTypeIntent, Name: "Play", Conditional: true},
+		{RequestType: golexa.RequestTypeIntent, Name: "Play"},
+		{RequestType: golexa.RequestTypeLaunch},
+		{RequestType: golexa.RequestTypeMessageReceived},
+	}, skill.Routes(), "Should list all routes, sorted by request type and name")
+
+	event := skill.Routes()[0]
+	suite.Equal(golexa.RequestTypeSkillDisabled, event.RequestType, "Should use the event type as a skill event's request type")
+	suite.Equal("", event.Name, "Should leave the name blank for skill events")
+}
+
+func (suite SkillSuite) TestValidate() {
+	noop := func(ctx context.Context, request golexa.Request) (golexa.Response, error) {
+		return golexa.NewResponse(request).Ok()
+	}
+
+	skill := golexa.Skill{}
+	skill.RouteIntent(golexa.IntentNameStop, noop)
+	skill.RouteIntent(golexa.IntentNameHelp, noop)
+
+	err := skill.Validate("Add")
+	suite.Require().IsType(golexa.RouteValidationError{}, err, "Should fail when built-in intents are missing")
+	suite.Equal([]string{"AMAZON.CancelIntent", "AMAZON.FallbackIntent", "AMAZON.NavigateHomeIntent", "Add"},
+		err.(golexa.RouteValidationError).MissingIntents, "Should report all missing built-in and required intents")
+	suite.Equal("golexa: missing routes for intents: AMAZON.CancelIntent, AMAZON.FallbackIntent, AMAZON.NavigateHomeIntent, Add",
+		err.Error(), "Should describe the missing intents")
+
+	for _, intentName := range golexa.BuiltInIntents {
+		skill.RouteIntent(intentName, noop)
+	}
+	suite.NoError(skill.Validate(), "Should pass once all built-ins are routed")
+	suite.Error(skill.Validate("Add"), "Should still fail when required intents are missing")
+
+	skill.RouteIntent("Add", noop).WhenSlot("item")
+	err = skill.Validate("Add")
+	suite.Require().IsType(golexa.RouteValidationError{}, err, "Should fail when required intents only have conditional routes")
+	suite.Empty(err.(golexa.RouteValidationError).MissingIntents, "Should not report conditional intents as missing")
+	suite.Equal([]string{"Add"}, err.(golexa.RouteValidationError).ConditionalIntents,
+		"Should report intents that only have conditional routes")
+	suite.Equal("golexa: only conditional routes for intents: Add", err.Error(), "Should describe the conditional intents")
+
+	skill.RouteIntent("Add", noop)
+	suite.NoError(skill.Validate("Add"), "Should pass once conditional intents have a plain route, too")
+}
+
+func (suite SkillSuite) TestValidateModel() {
+	noop := func(ctx context.Context, request golexa.Request) (golexa.Response, error) {
+		return golexa.NewResponse(request).Ok()
+	}
+	model := `{"interactionModel": {"languageModel": {"invocationName": "todo", "intents": [
+		{"name": "AMAZON.CancelIntent"}, {"name": "AMAZON.FallbackIntent"}, {"name": "AMAZON.HelpIntent"},
+		{"name": "AMAZON.NavigateHomeIntent"}, {"name": "AMAZON.StopIntent"},
+		{"name": "Add", "slots": [{"name": "item", "type": "AMAZON.Food"}]}, {"name": "Remove"}
+	]}}}`
+
+	skill := golexa.Skill{}
+	for _, intentName := range golexa.BuiltInIntents {
+		skill.RouteIntent(intentName, noop)
+	}
+	skill.RouteIntent("Add", noop)
+	skill.RouteIntent("Clear", noop)
+
+	err := skill.ValidateModel(strings.NewReader(model))
+	suite.Require().IsType(golexa.RouteValidationError{}, err, "Should fail when routes don't match the model")
+	suite.Equal([]string{"Remove"}, err.(golexa.RouteValidationError).MissingIntents,
+		"Should report intents in the model w/o routes")
+	suite.Equal([]string{"Clear"}, err.(golexa.RouteValidationError).UnknownIntents,
+		"Should report routes for intents that aren't in the model")
+
+	skill = golexa.Skill{}
+	for _, intentName := range golexa.BuiltInIntents {
+		skill.RouteIntent(intentName, noop)
+	}
+	skill.RouteIntent("Add", noop)
+	skill.RouteIntent("Remove", noop)
+	suite.NoError(skill.ValidateModel(strings.NewReader(model)), "Should pass when routes match the model")
+
+	suite.Error(skill.ValidateModel(strings.NewReader("not json")), "Should fail on malformed models")
+}
+
+func (suite SkillSuite) TestModel() {
+	noop := func(ctx context.Context, request golexa.Request) (golexa.Response, error) {
+		return golexa.NewResponse(request).Ok()
+	}
+
+	skill := golexa.Skill{}
+	skill.InvocationName(model.NewPhrases("media player"))
+	skill.SlotType("Media", model.NewSlotTypeValue("music", "song"))
+	skill.SlotType("Media", model.NewSlotTypeValue("podcast", "podcast"))
+	skill.RouteIntent(golexa.IntentNameStop, noop)
+	skill.RouteIntent("Play", noop).
+		WhenSlot("media", "podcast").
+		Samples(model.NewPhrases("play a {media}")).
+		Slot(model.Slot{Name: "media", Type: "Media"})
+	skill.Group().WithPrefix("Pl").RouteIntent("ay", noop).
+		Samples(model.NewPhrases("play something"))
+
+	m := skill.Model()
+	suite.Equal([]string{"media player"}, m.InvocationName.For(language.AmericanEnglish),
+		"Should include the invocation name")
+	suite.Require().Len(m.SlotTypes, 1, "Should replace slot types w/ the same name")
+	suite.Equal("podcast", m.SlotTypes[0].Values[0].ID, "Should replace slot types w/ the same name")
+
+	suite.Require().Len(m.Intents, 2, "Should include one intent per intent name")
+	suite.Equal(golexa.IntentNameStop, m.Intents[0].Name, "Should include intents w/o any declarations")
+	suite.Equal("Play", m.Intents[1].Name, "Should sort the intents by name")
+	suite.Equal([]string{"play a {media}", "play something"}, m.Intents[1].Samples.For(language.AmericanEnglish),
+		"Should combine the samples from all routes for the intent")
+	suite.Equal([]model.Slot{{Name: "media", Type: "Media"}}, m.Intents[1].Slots,
+		"Should include the slots from all routes for the intent")
+}
+
+func (suite SkillSuite) TestCatalog() {
+	catalog := speech.NewCatalog()
+	catalog.Add("greeting", speech.NewTemplate("Hello {{.Value}}",
+		speech.WithTranslation(language.Spanish, "Hola {{.Value}}")))
+	catalog.Add("reprompt", speech.NewTemplate("Are you there?"))
+
+	skill := golexa.Skill{}
+	skill.Catalog(catalog)
+	skill.RouteIntent("Greet", func(ctx context.Context, request golexa.Request) (golexa.Response, error) {
+		return golexa.NewResponse(request).
+			SpeakMessage("greeting", "Rob").
+			RepromptMessage("reprompt", nil).
+			SimpleCardMessage("reprompt", "greeting", "Rob").
+			Ok()
+	})
+	skill.RouteIntent("Typo", func(ctx context.Context, request golexa.Request) (golexa.Response, error) {
+		return golexa.NewResponse(request).SpeakMessage("greting", "Rob").RepromptMessage("nope", nil).Ok()
+	})
+
+	res, err := skill.Handle(context.TODO(), golexa.NewIntentRequest("Greet", golexa.NewSlots()))
+	suite.Require().NoError(err, "Should handle the request")
+	suite.Equal("<speak>Hello Rob</speak>", res.Body.OutputSpeech.SSML, "Should speak messages from the catalog")
+	suite.Equal("<speak>Are you there?</speak>", res.Body.Reprompt.OutputSpeech.SSML, "Should reprompt messages from the catalog")
+	suite.Equal("Are you there?", res.Body.Card.Title, "Should display card messages from the catalog")
+	suite.Equal("Hello Rob", res.Body.Card.Content, "Should display card messages from the catalog")
+
+	request := golexa.NewIntentRequest("Greet", golexa.NewSlots())
+	request.Body.Locale = "es-MX"
+	res, err = skill.Handle(context.TODO(), request)
+	suite.Require().NoError(err, "Should handle the request")
+	suite.Equal("<speak>Hola Rob</speak>", res.Body.OutputSpeech.SSML, "Should speak messages in the request's language")
+
+	res, err = skill.Handle(context.TODO(), golexa.NewIntentRequest("Typo", golexa.NewSlots()))
+	suite.Require().NoError(err, "Should handle the request")
+	suite.Contains(res.Body.OutputSpeech.SSML, "I'm sorry", "Should apologize for unknown messages")
+	suite.Contains(res.Body.Reprompt.OutputSpeech.SSML, "I'm sorry", "Should apologize for unknown reprompt messages")
+	suite.Equal([]string{"greting", "nope"}, catalog.Missing(), "Should track unknown messages")
+
+	res = golexa.NewResponse(golexa.NewIntentRequest("Greet", golexa.NewSlots())).SpeakMessage("greeting", "Rob")
+	suite.Contains(res.Body.OutputSpeech.SSML, "I'm sorry", "Should apologize when there's no catalog")
+}
+
+func (suite SkillSuite) TestTemplateErrors() {
+	broken := speech.NewTemplate("Hello {{.Value.Nope}}")
+	skill := golexa.Skill{}
+	skill.RouteIntent("Broken", func(ctx context.Context, request golexa.Request) (golexa.Response, error) {
+		return golexa.NewResponse(request).SpeakTemplate(broken, "Rob").Ok()
+	})
+	skill.RouteIntent("Card", func(ctx context.Context, request golexa.Request) (golexa.Response, error) {
+		return golexa.NewResponse(request).Speak("Hi").SimpleCardTemplate(broken, broken, "Rob").Ok()
+	})
+	skill.RouteIntent("Fine", func(ctx context.Context, request golexa.Request) (golexa.Response, error) {
+		return golexa.NewResponse(request).SpeakTemplate(speech.NewTemplate("Hello {{.Value}}"), "Rob").Ok()
+	})
+
+	logs := bytes.Buffer{}
+	logrus.SetOutput(&logs)
+	defer logrus.SetOutput(os.Stderr)
+
+	res, err := skill.Handle(context.TODO(), golexa.NewIntentRequest("Broken", golexa.NewSlots()))
+	suite.Require().NoError(err, "Should not fail the request by default")
+	suite.Contains(res.Body.OutputSpeech.SSML, "I'm sorry", "Should apologize by default")
+	suite.Error(res.Err(), "Should still report the template error")
+	suite.Empty(logs.String(), "Should leave logging the template error up to the caller")
+
+	skill.TemplateFallback(speech.NewTemplate("Oops. Try that again.",
+		speech.WithTranslation(language.Spanish, "Uy. Inténtalo de nuevo.")))
+	res, err = skill.Handle(context.TODO(), golexa.NewIntentRequest("Broken", golexa.NewSlots()))
+	suite.Require().NoError(err, "Should not fail the request w/ a fallback")
+	suite.Equal("<speak>Oops. Try that again.</speak>", res.Body.OutputSpeech.SSML, "Should speak the fallback")
+
+	request := golexa.NewIntentRequest("Broken", golexa.NewSlots())
+	request.Body.Locale = "es-MX"
+	res, _ = skill.Handle(context.TODO(), request)
+	suite.Equal("<speak>Uy. Inténtalo de nuevo.</speak>", res.Body.OutputSpeech.SSML, "Should speak the fallback in the request's language")
+
+	skill.TemplateFallback(speech.NewTemplate("{{.Value.Nope}}"))
+	res, _ = skill.Handle(context.TODO(), golexa.NewIntentRequest("Broken", golexa.NewSlots()))
+	suite.Contains(res.Body.OutputSpeech.SSML, "I'm sorry", "Should apologize when the fallback fails, too")
+	suite.Require().Error(res.Err(), "Should report the template error when the fallback fails")
+	suite.Contains(res.Err().Error(), "unable to speak fallback template", "Should report the fallback error, too")
+
+	skill.StrictTemplates(true)
+	_, err = skill.Handle(context.TODO(), golexa.NewIntentRequest("Broken", golexa.NewSlots()))
+	suite.Error(err, "Should fail the request in strict mode")
+	suite.Contains(err.Error(), "golexa: unable to speak template", "Should describe what failed")
+
+	_, err = skill.Handle(context.TODO(), golexa.NewIntentRequest("Card", golexa.NewSlots()))
+	suite.Error(err, "Should fail the request in strict mode when a card fails")
+
+	res, err = skill.Handle(context.TODO(), golexa.NewIntentRequest("Fine", golexa.NewSlots()))
+	suite.Require().NoError(err, "Should not fail the request when templates work in strict mode")
+	suite.NoError(res.Err(), "Should not report errors when templates work")
+	suite.Equal("<speak>Hello Rob</speak>", res.Body.OutputSpeech.SSML, "Should speak the template")
+}
@@ -0,0 +1,240 @@
+package golexa
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// MissingSlotsError is what `Slots.Bind()` returns when the user didn't provide a value for one or more
+// of your required slots. The slot names are in the same order as the fields in your struct, so you
+// can simply elicit the first one and let Alexa come back for the rest.
+type MissingSlotsError struct {
+	SlotNames []string
+}
+
+func (err MissingSlotsError) Error() string {
+	return "golexa: missing required slots: " + strings.Join(err.SlotNames, ", ")
+}
+
+// MissingSlots returns the names of the required slots that `Slots.Bind()` could not fill in. You get
+// an empty slice if the error is nil or has nothing to do w/ missing slots.
+func MissingSlots(err error) []string {
+	if missingErr, ok := err.(MissingSlotsError); ok {
+		return missingErr.SlotNames
+	}
+	return nil
+}
+
+var (
+	typeTime            = reflect.TypeOf(time.Time{})
+	typeDuration        = reflect.TypeOf(time.Duration(0))
+	typeDateRange       = reflect.TypeOf(DateRange{})
+	typeTimeOfDay       = reflect.TypeOf(TimeOfDay{})
+	typeStringSlice     = reflect.TypeOf([]string{})
+	typeTextUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Bind copies the resolved slot values into the fields of the struct that 'dst' points to, so you don't
+// need to juggle strings and parse numbers/dates yourself. Only fields w/ a "slot" tag are bound:
+//
+//	type AddItemSlots struct {
+//	    Name     string        `slot:"item_name,required"`
+//	    Quantity int           `slot:"quantity"`
+//	    Due      time.Time     `slot:"due_date"`
+//	    Every    time.Duration `slot:"frequency"`
+//	}
+//
+// Fields can be strings, []string (multi-value slots), any int type, bool ("yes"/"no"/"true"/"false"),
+// time.Time (the start of an AMAZON.DATE), DateRange, TimeOfDay, time.Duration, or any type that implements
+// encoding.TextUnmarshaler. Slots w/o a value leave their field alone. If any "required" slots are empty
+// or Alexa couldn't understand them, we still bind everything else and return a MissingSlotsError; use
+// `MissingSlots(err)` to figure out which one to `ElicitSlot()`. Values that can't be parsed result in a SlotError.
+//
+// Dates are resolved w/o knowing the user's locale, so seasons like "this summer" always resolve as
+// they do in the northern hemisphere. Use `Request.BindSlots()` (or `BindLanguage()`) if you care about
+// seasons in the southern hemisphere.
+func (s Slots) Bind(dst interface{}) error {
+	return s.BindLanguage(dst, language.Und)
+}
+
+// BindLanguage works just like `Bind()`, but resolves dates for the given language, so "this summer"
+// is December through February for an "en-AU" request. `Request.BindSlots()` does this for you.
+func (s Slots) BindLanguage(dst interface{}, lang language.Tag) error {
+	value := reflect.ValueOf(dst)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("golexa: bind destination must be a pointer to a struct, not %T", dst)
+	}
+	value = value.Elem()
+
+	var missing []string
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		slotName, required, ok := parseSlotTag(field)
+		if !ok {
+			continue
+		}
+		if !value.Field(i).CanSet() {
+			return fmt.Errorf("golexa: unable to bind slot '%s' to unexported field '%s'", slotName, field.Name)
+		}
+
+		err := s.bindField(slotName, value.Field(i), lang)
+		switch {
+		case err == nil:
+			continue
+		case isSlotMissing(err):
+			if required {
+				missing = append(missing, slotName)
+			}
+		default:
+			return err
+		}
+	}
+
+	if len(missing) > 0 {
+		return MissingSlotsError{SlotNames: missing}
+	}
+	return nil
+}
+
+// parseSlotTag extracts the slot name and options from a field's `slot:"name,required"` tag. The
+// boolean is false if the field should not be bound at all.
+func parseSlotTag(field reflect.StructField) (string, bool, bool) {
+	tag, ok := field.Tag.Lookup("slot")
+	if !ok || tag == "-" {
+		return "", false, false
+	}
+	parts := strings.Split(tag, ",")
+	slotName := strings.TrimSpace(parts[0])
+	if slotName == "" {
+		slotName = field.Name
+	}
+
+	required := false
+	for _, option := range parts[1:] {
+		if strings.TrimSpace(option) == "required" {
+			required = true
+		}
+	}
+	return slotName, required, true
+}
+
+// isSlotMissing returns true if the error indicates that we should ask the user for the slot again.
+func isSlotMissing(err error) bool {
+	slotErr, ok := err.(SlotError)
+	return ok && (slotErr.Err == ErrSlotEmpty || slotErr.Err == ErrSlotUnrecognized)
+}
+
+// bindField parses the slot's value based on the type of the field and assigns it.
+func (s Slots) bindField(slotName string, field reflect.Value, lang language.Tag) error {
+	switch field.Type() {
+	case typeStringSlice:
+		values := s.ResolveValues(slotName)
+		if len(values) == 0 {
+			return SlotError{SlotName: slotName, Err: ErrSlotEmpty}
+		}
+		field.Set(reflect.ValueOf(values))
+		return nil
+
+	case typeTime:
+		dateRange, err := s.Date(slotName, lang)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(dateRange.Start))
+		return nil
+
+	case typeDateRange:
+		dateRange, err := s.Date(slotName, lang)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(dateRange))
+		return nil
+
+	case typeTimeOfDay:
+		timeOfDay, err := s.Time(slotName)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(timeOfDay))
+		return nil
+
+	case typeDuration:
+		duration, err := s.Duration(slotName)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(duration))
+		return nil
+	}
+
+	// Check this before the kinds so that custom string types can still do their own parsing.
+	if reflect.PtrTo(field.Type()).Implements(typeTextUnmarshaler) {
+		value, err := s.typedValue(slotName, field.Type().String())
+		if err != nil {
+			return err
+		}
+		if err := field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value)); err != nil {
+			return SlotError{SlotName: slotName, SlotType: field.Type().String(), Value: value, Err: err}
+		}
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		value, err := s.typedValue(slotName, "string")
+		if err != nil {
+			return err
+		}
+		field.SetString(value)
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number, err := s.Int(slotName)
+		if err != nil {
+			return err
+		}
+		if field.OverflowInt(int64(number)) {
+			return SlotError{SlotName: slotName, SlotType: "AMAZON.NUMBER", Value: strconv.Itoa(number), Err: errors.New("number is out of range")}
+		}
+		field.SetInt(int64(number))
+		return nil
+
+	case reflect.Bool:
+		value, err := s.typedValue(slotName, "bool")
+		if err != nil {
+			return err
+		}
+		b, err := parseBool(value)
+		if err != nil {
+			return SlotError{SlotName: slotName, SlotType: "bool", Value: value, Err: err}
+		}
+		field.SetBool(b)
+		return nil
+	}
+
+	return fmt.Errorf("golexa: unable to bind slot '%s' to unsupported type %s", slotName, field.Type())
+}
+
+// parseBool understands the ways a user might answer a yes/no style custom slot in addition
+// to the usual values that strconv.ParseBool() supports.
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "yes", "yeah", "yep", "on", "sure":
+		return true, nil
+	case "no", "nope", "off":
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.New("not a yes/no value")
+	}
+	return b, nil
+}
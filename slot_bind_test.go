@@ -0,0 +1,130 @@
+package golexa_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robsignorelli/golexa"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSlotBindSuite(t *testing.T) {
+	suite.Run(t, new(SlotBindSuite))
+}
+
+type SlotBindSuite struct {
+	suite.Suite
+}
+
+type shoutText string
+
+func (s *shoutText) UnmarshalText(text []byte) error {
+	if string(text) == "quiet" {
+		return errors.New("too quiet")
+	}
+	*s = shoutText(strings.ToUpper(string(text)))
+	return nil
+}
+
+type bindTarget struct {
+	Name     string           `slot:"item_name,required"`
+	Quantity int              `slot:"quantity"`
+	Small    int8             `slot:"small"`
+	Urgent   bool             `slot:"urgent"`
+	Due      time.Time        `slot:"due"`
+	Period   golexa.DateRange `slot:"period"`
+	At       golexa.TimeOfDay `slot:"at"`
+	Every    time.Duration    `slot:"every"`
+	Tags     []string         `slot:"tags"`
+	Shout    shoutText        `slot:"shout"`
+	Ignored  string
+	Skipped  string `slot:"-"`
+}
+
+func (suite SlotBindSuite) TestBind() {
+	slots := golexa.NewSlots(
+		golexa.NewResolvedSlot("item_name", "wash clothes", "laundry"),
+		golexa.NewSlot("quantity", "3"),
+		golexa.NewSlot("urgent", "yes"),
+		golexa.NewSlot("due", "2026-10-20"),
+		golexa.NewSlot("period", "2026-10"),
+		golexa.NewSlot("at", "EV"),
+		golexa.NewSlot("every", "PT1H"),
+		golexa.NewListSlot("tags", "home", "chores"),
+		golexa.NewSlot("shout", "hello"),
+		golexa.NewSlot("Ignored", "nope"),
+		golexa.NewSlot("-", "nope"),
+	)
+
+	var target bindTarget
+	suite.Require().NoError(slots.Bind(&target), "Should bind all supported types")
+	suite.Equal("laundry", target.Name, "Should bind resolved string values")
+	suite.Equal(3, target.Quantity, "Should bind numbers")
+	suite.True(target.Urgent, "Should bind yes/no values as booleans")
+	suite.Equal(time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC), target.Due, "Should bind the start of dates")
+	suite.Equal(golexa.DateGranularityMonth, target.Period.Granularity, "Should bind date ranges")
+	suite.True(target.At.Vague(), "Should bind times of day")
+	suite.Equal(time.Hour, target.Every, "Should bind durations")
+	suite.Equal([]string{"home", "chores"}, target.Tags, "Should bind multi-value slots")
+	suite.Equal(shoutText("HELLO"), target.Shout, "Should bind text unmarshalers")
+	suite.Equal("", target.Ignored, "Should not bind untagged fields")
+	suite.Equal("", target.Skipped, "Should not bind fields tagged w/ '-'")
+}
+
+func (suite SlotBindSuite) TestBindLanguage() {
+	var target struct {
+		Season golexa.DateRange `slot:"season"`
+	}
+	request := golexa.NewIntentRequest("Plan", golexa.NewSlots(golexa.NewSlot("season", "2026-SU")))
+
+	suite.Require().NoError(request.BindSlots(&target), "Should bind the request's slots")
+	suite.Equal(time.Month(6), target.Season.Start.Month(), "Should resolve seasons in the northern hemisphere by default")
+
+	request.Body.Locale = "en-AU"
+	suite.Require().NoError(request.BindSlots(&target), "Should bind the request's slots")
+	suite.Equal(time.Month(12), target.Season.Start.Month(), "Should resolve seasons using the request's language")
+
+	suite.Require().NoError(request.Body.Intent.Slots.Bind(&target), "Should bind the slots")
+	suite.Equal(time.Month(6), target.Season.Start.Month(), "Should resolve seasons in the northern hemisphere w/o a language")
+
+	suite.Error(golexa.NewSkillEventRequest(golexa.RequestTypeSkillEnabled, "user.123").BindSlots(&target),
+		"Should fail to bind requests w/o an intent")
+}
+
+func (suite SlotBindSuite) TestBindMissing() {
+	target := bindTarget{Quantity: 7}
+	err := golexa.NewSlots(golexa.NewSlot("small", "5")).Bind(&target)
+	suite.Require().IsType(golexa.MissingSlotsError{}, err, "Should fail when required slots are empty")
+	suite.Equal([]string{"item_name"}, golexa.MissingSlots(err), "Should report the missing required slots")
+	suite.Equal(int8(5), target.Small, "Should still bind the slots that were provided")
+	suite.Equal(7, target.Quantity, "Should leave fields alone when their slot is empty")
+
+	err = golexa.NewSlots(golexa.NewSlot("item_name", "?")).Bind(&target)
+	suite.Equal([]string{"item_name"}, golexa.MissingSlots(err), "Should treat unrecognized required values as missing")
+
+	suite.Nil(golexa.MissingSlots(nil), "Should not report missing slots when there's no error")
+	suite.Nil(golexa.MissingSlots(errors.New("nope")), "Should not report missing slots for other errors")
+}
+
+func (suite SlotBindSuite) TestBindFailures() {
+	run := func(slot golexa.Slot) error {
+		var target bindTarget
+		return golexa.NewSlots(golexa.NewSlot("item_name", "laundry"), slot).Bind(&target)
+	}
+
+	suite.IsType(golexa.SlotError{}, run(golexa.NewSlot("quantity", "lots")), "Should fail on bad numbers")
+	suite.IsType(golexa.SlotError{}, run(golexa.NewSlot("small", "300")), "Should fail on numbers that overflow the field")
+	suite.IsType(golexa.SlotError{}, run(golexa.NewSlot("urgent", "maybe")), "Should fail on bad booleans")
+	suite.IsType(golexa.SlotError{}, run(golexa.NewSlot("due", "someday")), "Should fail on bad dates")
+	suite.IsType(golexa.SlotError{}, run(golexa.NewSlot("every", "1 hour")), "Should fail on bad durations")
+	suite.IsType(golexa.SlotError{}, run(golexa.NewSlot("shout", "quiet")), "Should fail when unmarshalers fail")
+
+	slots := golexa.NewSlots()
+	suite.Error(slots.Bind(bindTarget{}), "Should fail when not given a pointer")
+	suite.Error(slots.Bind(new(string)), "Should fail when not given a struct")
+	suite.Error(slots.Bind(&struct {
+		Value float64 `slot:"value"`
+	}{}), "Should fail on unsupported field types")
+}
@@ -0,0 +1,373 @@
+package golexa
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// ErrSlotEmpty indicates that the user did not provide a value for the slot (or the slot doesn't exist).
+var ErrSlotEmpty = errors.New("slot has no value")
+
+// ErrSlotUnrecognized indicates that Alexa heard the user say something for the slot but could not
+// make sense of it. This happens when Alexa sends a value of "?", such as a number slot where the user
+// said "a bunch".
+var ErrSlotUnrecognized = errors.New("slot value was not recognized")
+
+// SlotError is the error returned by the typed slot accessors such as `Slots.Int()` and `Slots.Date()`
+// when the slot's value can't be converted. Err is ErrSlotEmpty, ErrSlotUnrecognized, or a description
+// of why the value could not be parsed.
+type SlotError struct {
+	SlotName string
+	SlotType string
+	Value    string
+	Err      error
+}
+
+func (err SlotError) Error() string {
+	switch err.Err {
+	case ErrSlotEmpty, ErrSlotUnrecognized:
+		return fmt.Sprintf("golexa: slot '%s': %v", err.SlotName, err.Err)
+	default:
+		return fmt.Sprintf("golexa: slot '%s': unable to parse '%s' as %s: %v", err.SlotName, err.Value, err.SlotType, err.Err)
+	}
+}
+
+// Unwrap exposes the underlying cause so you can use `errors.Is(err, golexa.ErrSlotEmpty)`.
+func (err SlotError) Unwrap() error {
+	return err.Err
+}
+
+// IsSlotEmpty returns true if the error came from a typed slot accessor because the user
+// did not provide a value for the slot.
+func IsSlotEmpty(err error) bool {
+	slotErr, ok := err.(SlotError)
+	return ok && slotErr.Err == ErrSlotEmpty
+}
+
+// Int parses the resolved value of an AMAZON.NUMBER or AMAZON.FOUR_DIGIT_NUMBER slot. Keep in mind
+// that you will lose any leading zeros of four digit numbers, so use `Resolve()` when you need the raw digits.
+func (s Slots) Int(slotName string) (int, error) {
+	value, err := s.typedValue(slotName, "AMAZON.NUMBER")
+	if err != nil {
+		return 0, err
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, SlotError{SlotName: slotName, SlotType: "AMAZON.NUMBER", Value: value, Err: errors.New("not a whole number")}
+	}
+	return number, nil
+}
+
+// Date parses the resolved value of an AMAZON.DATE slot. Since users can say things like "next week"
+// or "this summer", the result is a range of time rather than a single day. The language (use `Request.Language()`)
+// is used to resolve values that differ by region, such as seasons in the southern hemisphere.
+func (s Slots) Date(slotName string, lang language.Tag) (DateRange, error) {
+	value, err := s.typedValue(slotName, "AMAZON.DATE")
+	if err != nil {
+		return DateRange{}, err
+	}
+	dateRange, err := ParseDate(value, lang, time.Now())
+	if err != nil {
+		return DateRange{}, SlotError{SlotName: slotName, SlotType: "AMAZON.DATE", Value: value, Err: err}
+	}
+	return dateRange, nil
+}
+
+// Time parses the resolved value of an AMAZON.TIME slot. This can be an exact time like "14:25" or a
+// vague time of day like "this evening".
+func (s Slots) Time(slotName string) (TimeOfDay, error) {
+	value, err := s.typedValue(slotName, "AMAZON.TIME")
+	if err != nil {
+		return TimeOfDay{}, err
+	}
+	timeOfDay, err := ParseTime(value)
+	if err != nil {
+		return TimeOfDay{}, SlotError{SlotName: slotName, SlotType: "AMAZON.TIME", Value: value, Err: err}
+	}
+	return timeOfDay, nil
+}
+
+// Duration parses the resolved value of an AMAZON.DURATION slot (e.g. "PT1H30M").
+func (s Slots) Duration(slotName string) (time.Duration, error) {
+	value, err := s.typedValue(slotName, "AMAZON.DURATION")
+	if err != nil {
+		return 0, err
+	}
+	duration, err := ParseDuration(value)
+	if err != nil {
+		return 0, SlotError{SlotName: slotName, SlotType: "AMAZON.DURATION", Value: value, Err: err}
+	}
+	return duration, nil
+}
+
+// typedValue resolves the slot, failing if there's nothing there for us to parse.
+func (s Slots) typedValue(slotName, slotType string) (string, error) {
+	value := strings.TrimSpace(s.Resolve(slotName))
+	switch value {
+	case "":
+		return "", SlotError{SlotName: slotName, SlotType: slotType, Err: ErrSlotEmpty}
+	case "?":
+		return "", SlotError{SlotName: slotName, SlotType: slotType, Value: value, Err: ErrSlotUnrecognized}
+	default:
+		return value, nil
+	}
+}
+
+// DateGranularity describes how specific the user was when they uttered a date.
+type DateGranularity string
+
+// The different levels of specificity for an AMAZON.DATE value.
+const (
+	DateGranularityDay     = DateGranularity("DAY")
+	DateGranularityWeek    = DateGranularity("WEEK")
+	DateGranularityWeekend = DateGranularity("WEEKEND")
+	DateGranularityMonth   = DateGranularity("MONTH")
+	DateGranularitySeason  = DateGranularity("SEASON")
+	DateGranularityYear    = DateGranularity("YEAR")
+	DateGranularityDecade  = DateGranularity("DECADE")
+	DateGranularityPresent = DateGranularity("PRESENT")
+)
+
+// DateRange is the span of time that the user referred to. Start is inclusive and End is exclusive, so
+// "today" starts at midnight today and ends at midnight tomorrow. Since Alexa doesn't tell us the user's
+// time zone, all dates are in UTC; use the same year/month/day in the user's zone if you know it.
+type DateRange struct {
+	Start       time.Time
+	End         time.Time
+	Granularity DateGranularity
+}
+
+// Contains returns true if the given time falls within this range.
+func (r DateRange) Contains(t time.Time) bool {
+	return !t.Before(r.Start) && t.Before(r.End)
+}
+
+var (
+	patternDay     = regexp.MustCompile(`^(\d{4}|XXXX)-(\d{2}|XX)-(\d{2}|XX)$`)
+	patternWeek    = regexp.MustCompile(`^(\d{4}|XXXX)-W(\d{1,2})(-WE)?$`)
+	patternMonth   = regexp.MustCompile(`^(\d{4}|XXXX)-(\d{2})$`)
+	patternSeason  = regexp.MustCompile(`^(\d{4}|XXXX)-(WI|SP|SU|FA)$`)
+	patternYear    = regexp.MustCompile(`^\d{4}$`)
+	patternDecade  = regexp.MustCompile(`^(\d{3})X$`)
+	patternHourMin = regexp.MustCompile(`^(\d{2}):(\d{2})(:\d{2})?$`)
+	patternISODur  = regexp.MustCompile(`^P(?:(\d+(?:\.\d+)?)Y)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)W)?(?:(\d+(?:\.\d+)?)D)?(?:T(?:(\d+(?:\.\d+)?)H)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+)
+
+// southernHemisphere contains the regions whose seasons are flipped relative to the northern hemisphere.
+var southernHemisphere = map[language.Region]bool{
+	language.MustParseRegion("AU"): true,
+	language.MustParseRegion("BR"): true,
+	language.MustParseRegion("NZ"): true,
+	language.MustParseRegion("ZA"): true,
+}
+
+// ParseDate converts the raw value of an AMAZON.DATE slot into the range of time it represents. The
+// 'now' time is used to resolve relative values like "PRESENT_REF" and dates where the user didn't
+// specify the year. You typically want to use `Slots.Date()` instead.
+//
+// See: https://developer.amazon.com/en-US/docs/alexa/custom-skills/slot-type-reference.html#date
+func ParseDate(value string, lang language.Tag, now time.Time) (DateRange, error) {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch {
+	case value == "PRESENT_REF":
+		// End is exclusive, so we need a sliver of time for the range to contain 'now' at all.
+		return DateRange{Start: now, End: now.Add(time.Nanosecond), Granularity: DateGranularityPresent}, nil
+
+	case patternDay.MatchString(value):
+		parts := patternDay.FindStringSubmatch(value)
+		return parseDay(parts[1], parts[2], parts[3], today)
+
+	case patternWeek.MatchString(value):
+		parts := patternWeek.FindStringSubmatch(value)
+		week, _ := strconv.Atoi(parts[2])
+		if week < 1 || week > 53 {
+			return DateRange{}, fmt.Errorf("invalid week number %d", week)
+		}
+		start := isoWeekStart(parseYear(parts[1], today), week)
+		if parts[3] != "" {
+			start = start.AddDate(0, 0, 5)
+			return DateRange{Start: start, End: start.AddDate(0, 0, 2), Granularity: DateGranularityWeekend}, nil
+		}
+		return DateRange{Start: start, End: start.AddDate(0, 0, 7), Granularity: DateGranularityWeek}, nil
+
+	case patternMonth.MatchString(value):
+		parts := patternMonth.FindStringSubmatch(value)
+		month, _ := strconv.Atoi(parts[2])
+		if month < 1 || month > 12 {
+			return DateRange{}, fmt.Errorf("invalid month %d", month)
+		}
+		start := time.Date(parseYear(parts[1], today), time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+		return DateRange{Start: start, End: start.AddDate(0, 1, 0), Granularity: DateGranularityMonth}, nil
+
+	case patternSeason.MatchString(value):
+		parts := patternSeason.FindStringSubmatch(value)
+		return parseSeason(parseYear(parts[1], today), parts[2], lang), nil
+
+	case patternYear.MatchString(value):
+		year, _ := strconv.Atoi(value)
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return DateRange{Start: start, End: start.AddDate(1, 0, 0), Granularity: DateGranularityYear}, nil
+
+	case patternDecade.MatchString(value):
+		decade, _ := strconv.Atoi(patternDecade.FindStringSubmatch(value)[1])
+		start := time.Date(decade*10, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return DateRange{Start: start, End: start.AddDate(10, 0, 0), Granularity: DateGranularityDecade}, nil
+
+	default:
+		return DateRange{}, errors.New("unsupported date format")
+	}
+}
+
+// parseDay handles the "YYYY-MM-DD" format, where any of the components may be X'd out because
+// the user didn't specify them (e.g. "2026-XX-XX" for "in 2026" or "XXXX-11-24" for "November 24th").
+func parseDay(yearText, monthText, dayText string, today time.Time) (DateRange, error) {
+	if monthText == "XX" {
+		if dayText != "XX" {
+			return DateRange{}, errors.New("day specified without a month")
+		}
+		start := time.Date(parseYear(yearText, today), time.January, 1, 0, 0, 0, 0, time.UTC)
+		return DateRange{Start: start, End: start.AddDate(1, 0, 0), Granularity: DateGranularityYear}, nil
+	}
+
+	month, _ := strconv.Atoi(monthText)
+	if month < 1 || month > 12 {
+		return DateRange{}, fmt.Errorf("invalid month %d", month)
+	}
+	if dayText == "XX" {
+		start := time.Date(parseYear(yearText, today), time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+		return DateRange{Start: start, End: start.AddDate(0, 1, 0), Granularity: DateGranularityMonth}, nil
+	}
+
+	day, _ := strconv.Atoi(dayText)
+	year := parseYear(yearText, today)
+	start := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if start.Month() != time.Month(month) {
+		return DateRange{}, fmt.Errorf("invalid day %d for month %d", day, month)
+	}
+	// They said "November 24th" w/o a year, so assume the next one rather than one in the past.
+	if yearText == "XXXX" && start.Before(today) {
+		start = start.AddDate(1, 0, 0)
+	}
+	return DateRange{Start: start, End: start.AddDate(0, 0, 1), Granularity: DateGranularityDay}, nil
+}
+
+// parseSeason uses meteorological seasons (e.g. summer in the north is June, July, and August). Winter
+// starts in December of the given year and runs through February of the following year.
+func parseSeason(year int, season string, lang language.Tag) DateRange {
+	startMonths := map[string]time.Month{
+		"SP": time.March,
+		"SU": time.June,
+		"FA": time.September,
+		"WI": time.December,
+	}
+	region, _ := lang.Region()
+	if southernHemisphere[region] {
+		startMonths = map[string]time.Month{
+			"FA": time.March,
+			"WI": time.June,
+			"SP": time.September,
+			"SU": time.December,
+		}
+	}
+	start := time.Date(year, startMonths[season], 1, 0, 0, 0, 0, time.UTC)
+	return DateRange{Start: start, End: start.AddDate(0, 3, 0), Granularity: DateGranularitySeason}
+}
+
+func parseYear(yearText string, today time.Time) int {
+	if year, err := strconv.Atoi(yearText); err == nil {
+		return year
+	}
+	return today.Year()
+}
+
+// isoWeekStart finds the Monday that begins the given ISO-8601 week of the year.
+func isoWeekStart(year, week int) time.Time {
+	// January 4th is always in week 1, so back up to its Monday and then jump ahead.
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	offset := (int(jan4.Weekday()) + 6) % 7
+	return jan4.AddDate(0, 0, -offset+(week-1)*7)
+}
+
+// TimeOfDay is the time that the user uttered for an AMAZON.TIME slot. When they said something vague
+// like "this evening", Period will be one of "MO", "AF", "EV", or "NI" and Hour/Minute are the typical
+// start of that part of the day.
+type TimeOfDay struct {
+	Hour   int
+	Minute int
+	Period string
+}
+
+// Vague returns true if the user said something like "in the morning" rather than an exact time.
+func (t TimeOfDay) Vague() bool {
+	return t.Period != ""
+}
+
+// On returns the instant on the given day that matches this time of day.
+func (t TimeOfDay) On(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), t.Hour, t.Minute, 0, 0, date.Location())
+}
+
+var timePeriods = map[string]TimeOfDay{
+	"MO": {Hour: 6, Period: "MO"},
+	"AF": {Hour: 12, Period: "AF"},
+	"EV": {Hour: 17, Period: "EV"},
+	"NI": {Hour: 21, Period: "NI"},
+}
+
+// ParseTime converts the raw value of an AMAZON.TIME slot into its time of day. You typically want
+// to use `Slots.Time()` instead.
+func ParseTime(value string) (TimeOfDay, error) {
+	if period, ok := timePeriods[value]; ok {
+		return period, nil
+	}
+	parts := patternHourMin.FindStringSubmatch(value)
+	if parts == nil {
+		return TimeOfDay{}, errors.New("unsupported time format")
+	}
+	hour, _ := strconv.Atoi(parts[1])
+	minute, _ := strconv.Atoi(parts[2])
+	if hour > 23 || minute > 59 {
+		return TimeOfDay{}, errors.New("time out of range")
+	}
+	return TimeOfDay{Hour: hour, Minute: minute}, nil
+}
+
+// ParseDuration converts the raw ISO-8601 value of an AMAZON.DURATION slot (e.g. "PT1H30M") into a
+// Go duration. Since months and years vary in length, they are approximated as 30 and 365 days. You
+// typically want to use `Slots.Duration()` instead.
+func ParseDuration(value string) (time.Duration, error) {
+	parts := patternISODur.FindStringSubmatch(value)
+	if parts == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, errors.New("unsupported duration format")
+	}
+
+	units := []time.Duration{
+		365 * 24 * time.Hour,
+		30 * 24 * time.Hour,
+		7 * 24 * time.Hour,
+		24 * time.Hour,
+		time.Hour,
+		time.Minute,
+		time.Second,
+	}
+	total := time.Duration(0)
+	for i, unit := range units {
+		if parts[i+1] == "" {
+			continue
+		}
+		amount, err := strconv.ParseFloat(parts[i+1], 64)
+		if err != nil {
+			return 0, err
+		}
+		total += time.Duration(amount * float64(unit))
+	}
+	return total, nil
+}
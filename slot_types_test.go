@@ -0,0 +1,204 @@
+package golexa_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robsignorelli/golexa"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/text/language"
+)
+
+func TestSlotTypesSuite(t *testing.T) {
+	suite.Run(t, new(SlotTypesSuite))
+}
+
+type SlotTypesSuite struct {
+	suite.Suite
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func (suite SlotTypesSuite) TestInt() {
+	slots := golexa.NewSlots(
+		golexa.NewSlot("count", "42"),
+		golexa.NewSlot("negative", "-5"),
+		golexa.NewSlot("pin", "0123"),
+		golexa.NewSlot("unknown", "?"),
+		golexa.NewSlot("words", "a bunch"),
+	)
+
+	value, err := slots.Int("count")
+	suite.NoError(err, "Should parse positive numbers")
+	suite.Equal(42, value, "Should parse positive numbers")
+
+	value, err = slots.Int("negative")
+	suite.NoError(err, "Should parse negative numbers")
+	suite.Equal(-5, value, "Should parse negative numbers")
+
+	value, err = slots.Int("pin")
+	suite.NoError(err, "Should parse four digit numbers")
+	suite.Equal(123, value, "Should parse four digit numbers")
+
+	_, err = slots.Int("missing")
+	suite.True(golexa.IsSlotEmpty(err), "Should report missing slots as empty")
+
+	_, err = slots.Int("unknown")
+	suite.Require().IsType(golexa.SlotError{}, err, "Should return a SlotError for unrecognized values")
+	suite.Equal(golexa.ErrSlotUnrecognized, err.(golexa.SlotError).Err, "Should report '?' as unrecognized")
+
+	_, err = slots.Int("words")
+	suite.Require().IsType(golexa.SlotError{}, err, "Should return a SlotError for unparseable values")
+	suite.Equal("golexa: slot 'words': unable to parse 'a bunch' as AMAZON.NUMBER: not a whole number", err.Error(),
+		"Should describe exactly which slot/value failed")
+}
+
+func (suite SlotTypesSuite) TestParseDate() {
+	now := time.Date(2026, time.October, 18, 15, 30, 0, 0, time.UTC)
+	english := language.AmericanEnglish
+	australian := language.MustParse("en-AU")
+
+	run := func(value string, lang language.Tag) golexa.DateRange {
+		dateRange, err := golexa.ParseDate(value, lang, now)
+		suite.Require().NoError(err, "Should parse valid date: "+value)
+		return dateRange
+	}
+
+	r := run("2026-10-20", english)
+	suite.Equal(golexa.DateRange{Start: date(2026, 10, 20), End: date(2026, 10, 21), Granularity: golexa.DateGranularityDay}, r,
+		"Should parse exact dates as a single day")
+
+	r = run("XXXX-03-01", english)
+	suite.Equal(date(2027, 3, 1), r.Start,
+		"Should assume the next occurrence of dates w/o a year")
+
+	r = run("2026-W42", english)
+	suite.Equal(golexa.DateRange{Start: date(2026, 10, 12), End: date(2026, 10, 19), Granularity: golexa.DateGranularityWeek}, r,
+		"Should parse ISO weeks starting on Monday")
+
+	r = run("2026-W42-WE", english)
+	suite.Equal(golexa.DateRange{Start: date(2026, 10, 17), End: date(2026, 10, 19), Granularity: golexa.DateGranularityWeekend}, r,
+		"Should parse weekends as Saturday and Sunday")
+
+	r = run("2026-10", english)
+	suite.Equal(golexa.DateRange{Start: date(2026, 10, 1), End: date(2026, 11, 1), Granularity: golexa.DateGranularityMonth}, r,
+		"Should parse months")
+
+	r = run("2026-10-XX", english)
+	suite.Equal(golexa.DateRange{Start: date(2026, 10, 1), End: date(2026, 11, 1), Granularity: golexa.DateGranularityMonth}, r,
+		"Should parse months w/ an unspecified day")
+
+	r = run("2026-XX-XX", english)
+	suite.Equal(golexa.DateRange{Start: date(2026, 1, 1), End: date(2027, 1, 1), Granularity: golexa.DateGranularityYear}, r,
+		"Should parse years w/ unspecified month and day")
+
+	r = run("2026", english)
+	suite.Equal(golexa.DateRange{Start: date(2026, 1, 1), End: date(2027, 1, 1), Granularity: golexa.DateGranularityYear}, r,
+		"Should parse years")
+
+	r = run("202X", english)
+	suite.Equal(golexa.DateRange{Start: date(2020, 1, 1), End: date(2030, 1, 1), Granularity: golexa.DateGranularityDecade}, r,
+		"Should parse decades")
+
+	r = run("2026-SU", english)
+	suite.Equal(golexa.DateRange{Start: date(2026, 6, 1), End: date(2026, 9, 1), Granularity: golexa.DateGranularitySeason}, r,
+		"Should parse northern hemisphere seasons")
+
+	r = run("2026-SU", australian)
+	suite.Equal(golexa.DateRange{Start: date(2026, 12, 1), End: date(2027, 3, 1), Granularity: golexa.DateGranularitySeason}, r,
+		"Should parse southern hemisphere seasons for southern locales")
+
+	r = run("PRESENT_REF", english)
+	suite.Equal(golexa.DateRange{Start: now, End: now.Add(time.Nanosecond), Granularity: golexa.DateGranularityPresent}, r,
+		"Should resolve PRESENT_REF to the current time")
+	suite.True(r.Contains(now),
+		"Should resolve PRESENT_REF to a range that contains the current time")
+	suite.False(r.Contains(now.Add(time.Second)),
+		"Should resolve PRESENT_REF to a range that only contains the current time")
+
+	for _, value := range []string{"tomorrow", "2026-13", "2026-02-30", "2026-W60", "2026-XX-05"} {
+		_, err := golexa.ParseDate(value, english, now)
+		suite.Error(err, "Should fail to parse invalid date: "+value)
+	}
+}
+
+func (suite SlotTypesSuite) TestDate() {
+	slots := golexa.NewSlots(
+		golexa.NewSlot("when", "2026-10-20"),
+		golexa.NewSlot("unknown", "?"),
+		golexa.NewSlot("bad", "someday"),
+	)
+
+	r, err := slots.Date("when", language.AmericanEnglish)
+	suite.NoError(err, "Should parse valid dates")
+	suite.True(r.Contains(time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC)), "Should contain times on that day")
+	suite.False(r.Contains(date(2026, 10, 21)), "Should not contain times after that day")
+
+	_, err = slots.Date("missing", language.AmericanEnglish)
+	suite.True(golexa.IsSlotEmpty(err), "Should report missing slots as empty")
+
+	_, err = slots.Date("unknown", language.AmericanEnglish)
+	suite.Equal(golexa.ErrSlotUnrecognized, err.(golexa.SlotError).Err, "Should report '?' as unrecognized")
+
+	_, err = slots.Date("bad", language.AmericanEnglish)
+	suite.Equal("AMAZON.DATE", err.(golexa.SlotError).SlotType, "Should report the failed slot type")
+}
+
+func (suite SlotTypesSuite) TestTime() {
+	slots := golexa.NewSlots(
+		golexa.NewSlot("exact", "14:25"),
+		golexa.NewSlot("evening", "EV"),
+		golexa.NewSlot("bad", "25:00"),
+	)
+
+	t, err := slots.Time("exact")
+	suite.NoError(err, "Should parse exact times")
+	suite.Equal(golexa.TimeOfDay{Hour: 14, Minute: 25}, t, "Should parse exact times")
+	suite.False(t.Vague(), "Exact times should not be vague")
+	suite.Equal(time.Date(2026, 10, 18, 14, 25, 0, 0, time.UTC), t.On(date(2026, 10, 18)),
+		"Should apply the time to a specific day")
+
+	t, err = slots.Time("evening")
+	suite.NoError(err, "Should parse vague times of day")
+	suite.Equal(golexa.TimeOfDay{Hour: 17, Period: "EV"}, t, "Should parse vague times of day")
+	suite.True(t.Vague(), "Times of day should be vague")
+
+	_, err = slots.Time("bad")
+	suite.Error(err, "Should fail on out of range times")
+	_, err = slots.Time("missing")
+	suite.True(golexa.IsSlotEmpty(err), "Should report missing slots as empty")
+}
+
+func (suite SlotTypesSuite) TestDuration() {
+	slots := golexa.NewSlots(
+		golexa.NewSlot("short", "PT1H30M"),
+		golexa.NewSlot("long", "P1DT2H"),
+		golexa.NewSlot("weeks", "P2W"),
+		golexa.NewSlot("seconds", "PT10S"),
+		golexa.NewSlot("bad", "1 hour"),
+		golexa.NewSlot("empty", "PT"),
+	)
+
+	d, err := slots.Duration("short")
+	suite.NoError(err, "Should parse hours and minutes")
+	suite.Equal(90*time.Minute, d, "Should parse hours and minutes")
+
+	d, err = slots.Duration("long")
+	suite.NoError(err, "Should parse days and hours")
+	suite.Equal(26*time.Hour, d, "Should parse days and hours")
+
+	d, err = slots.Duration("weeks")
+	suite.NoError(err, "Should parse weeks")
+	suite.Equal(14*24*time.Hour, d, "Should parse weeks")
+
+	d, err = slots.Duration("seconds")
+	suite.NoError(err, "Should parse seconds")
+	suite.Equal(10*time.Second, d, "Should parse seconds")
+
+	_, err = slots.Duration("bad")
+	suite.Error(err, "Should fail on non-ISO durations")
+	_, err = slots.Duration("empty")
+	suite.Error(err, "Should fail on durations w/o any components")
+}
@@ -1,6 +1,26 @@
 package golexa
 
-const resolutionSuccessCode = "ER_SUCCESS_MATCH"
+import "strings"
+
+// The status codes that Alexa reports for each entity resolution authority.
+//
+// See: https://developer.amazon.com/en-US/docs/alexa/custom-skills/entity-resolution.html#er-status-codes
+const (
+	ResolutionSuccessMatch   = "ER_SUCCESS_MATCH"
+	ResolutionSuccessNoMatch = "ER_SUCCESS_NO_MATCH"
+	ResolutionErrorTimeout   = "ER_ERROR_TIMEOUT"
+	ResolutionErrorException = "ER_ERROR_EXCEPTION"
+)
+
+// dynamicAuthorityPrefix is how Alexa identifies resolutions that matched your dynamic entities
+// rather than the static values in your interaction model.
+const dynamicAuthorityPrefix = "amzn1.er-authority.echo-sdk.dynamic"
+
+// The types of values that can appear in a slot's "slotValue".
+const (
+	SlotValueTypeSimple = "Simple"
+	SlotValueTypeList   = "List"
+)
 
 // Slots represents a set of runtime values that the Alexa interaction model parsed out for you.
 type Slots map[string]Slot
@@ -9,18 +29,68 @@ type Slots map[string]Slot
 // open-ended bit of text as you'd have in an AMAZON.SearchQuery slot or one of the phrases w/
 // synonyms you set up in a custom slot.
 type Slot struct {
-	Name        string      `json:"name"`
-	Value       string      `json:"value"`
-	Resolutions resolutions `json:"resolutions"`
+	Name               string      `json:"name"`
+	Value              string      `json:"value"`
+	Resolutions        Resolutions `json:"resolutions"`
+	ConfirmationStatus string      `json:"confirmationStatus,omitempty"`
+	Source             string      `json:"source,omitempty"`
+	SlotValue          *SlotValue  `json:"slotValue,omitempty"`
+}
+
+// SlotValue is the newer representation of what the user said for a slot. For most slots it is a single
+// "Simple" value, but slots that allow multiple values contain a "List" of simple values, one for each
+// thing the user said (e.g. "add eggs, milk, and bread to my list").
+type SlotValue struct {
+	Type        string      `json:"type"`
+	Value       string      `json:"value,omitempty"`
+	Resolutions Resolutions `json:"resolutions"`
+	Values      []SlotValue `json:"values,omitempty"`
+}
+
+// Resolve takes into account the synonyms and resolutions, returning the mapped value that the Alexa API
+// thinks we want. If there was no resolution data, you'll simply get back the transcribed text.
+func (value SlotValue) Resolve() string {
+	if match, ok := value.Resolutions.best(); ok {
+		return match.Name
+	}
+	return value.Value
+}
+
+// ResolveID returns the id of the entity that this value resolved to, preferring matches on your
+// dynamic entities over your static slot values. This is blank if nothing matched.
+func (value SlotValue) ResolveID() string {
+	match, _ := value.Resolutions.best()
+	return match.ID
+}
+
+// clone copies the value (and any nested values), keeping only the resolved value of each.
+func (value SlotValue) clone() SlotValue {
+	clone := SlotValue{Type: value.Type}
+	if value.Type != SlotValueTypeList {
+		clone.Value = value.Resolve()
+	}
+	for _, v := range value.Values {
+		clone.Values = append(clone.Values, v.clone())
+	}
+	return clone
 }
 
 // Clone creates a copy of all of the slots and their RESOLVED values. Typically you use this when you
 // want to include a set of slots in your response w/o modifying the map in the request. Be aware that
 // while it preserves the resolved value, you will lose the resolution authority data from the original.
+// Multi-value slots keep their list structure, so each value is still echoed back individually.
 func (s Slots) Clone() Slots {
 	slots := Slots{}
 	for slotName, slot := range s {
-		slots[slotName] = Slot{Name: slot.Name, Value: slot.Resolve()}
+		clone := Slot{Name: slot.Name, Value: slot.Resolve()}
+		if slot.SlotValue != nil {
+			slotValue := slot.SlotValue.clone()
+			clone.SlotValue = &slotValue
+			if slotValue.Type == SlotValueTypeList {
+				clone.Value = ""
+			}
+		}
+		slots[slotName] = clone
 	}
 	return slots
 }
@@ -33,71 +103,202 @@ func (s Slots) Resolve(slotName string) string {
 	return ""
 }
 
+// ResolveValues locates the specified slot entry and returns the resolved value of each thing
+// the user said for it. This is typically used for slots that allow multiple values.
+func (s Slots) ResolveValues(slotName string) []string {
+	var resolvedValues []string
+	for _, value := range s[slotName].Values() {
+		resolvedValues = append(resolvedValues, value.Resolve())
+	}
+	return resolvedValues
+}
+
 // Resolve takes into account the synonyms and resolutions, returning the mapped value that
 // the Alexa API thinks we want. If there was no resolution data, you'll simply get back the
-// transcribed text from what the user actually said.
+// transcribed text from what the user actually said. For slots where the user said multiple
+// values, you get all of the resolved values separated by commas; use `Values()` to get at them
+// individually.
 func (slot Slot) Resolve() string {
 	// They spoke a synonym for your custom slot or they said something like "this month" and it
 	// resolved to the ISO date string.
-	if resolvedValue := slot.Resolutions.ResolutionPerAuthority.resolvedValue(); resolvedValue != "" {
-		return resolvedValue
+	if match, ok := slot.Resolutions.best(); ok {
+		return match.Name
 	}
 
 	// There was no synonym/mapping for what the user spoke, so use their exact word(s)
-	return slot.Value
+	if slot.Value != "" || slot.SlotValue == nil {
+		return slot.Value
+	}
+
+	// Multi-value slots don't have a top-level value, so look at the individual values instead.
+	var resolvedValues []string
+	for _, value := range slot.Values() {
+		resolvedValues = append(resolvedValues, value.Resolve())
+	}
+	return strings.Join(resolvedValues, ", ")
+}
+
+// Values returns each of the individual values the user spoke for this slot along with their own
+// resolutions. For single value slots, this will contain just the one value (or nothing if the user
+// didn't fill in the slot).
+func (slot Slot) Values() []SlotValue {
+	switch {
+	case slot.SlotValue != nil && slot.SlotValue.Type == SlotValueTypeList:
+		return slot.SlotValue.Values
+	case slot.SlotValue != nil:
+		return []SlotValue{*slot.SlotValue}
+	case slot.Value != "":
+		return []SlotValue{{Type: SlotValueTypeSimple, Value: slot.Value, Resolutions: slot.Resolutions}}
+	default:
+		return nil
+	}
+}
+
+// ResolveID returns the id of the entity that the slot resolved to, preferring matches on your
+// dynamic entities over your static slot values. This is the canonical id you set up for the value
+// in your interaction model (or dynamic entities), so it's what you typically want to store in your
+// database. It is blank if nothing matched or if the user said multiple values.
+func (slot Slot) ResolveID() string {
+	return slot.resolutions().ResolveID()
 }
 
-type resolutions struct {
-	ResolutionPerAuthority resolutionAuthorities `json:"resolutionsPerAuthority"`
+// Matches returns every entity that the slot resolved to across all authorities, with matches on your
+// dynamic entities first. It is empty if nothing matched or if the user said multiple values.
+func (slot Slot) Matches() []ResolvedValue {
+	return slot.resolutions().Matches()
 }
 
-type resolutionAuthorities []resolutionPerAuthority
+// Authorities returns the raw entity resolution results for each authority that Alexa consulted
+// (your static slot values and/or your dynamic entities) including their status codes.
+func (slot Slot) Authorities() []ResolutionAuthority {
+	return slot.resolutions().ResolutionPerAuthority
+}
 
-func (resolutions resolutionAuthorities) resolvedValue() string {
-	if len(resolutions) == 0 {
-		return ""
+// resolutions finds the resolution data for the slot, which is either at the top level or in
+// the single value of the newer "slotValue" format.
+func (slot Slot) resolutions() Resolutions {
+	if len(slot.Resolutions.ResolutionPerAuthority) > 0 {
+		return slot.Resolutions
+	}
+	if slot.SlotValue != nil && slot.SlotValue.Type != SlotValueTypeList {
+		return slot.SlotValue.Resolutions
 	}
+	return Resolutions{}
+}
+
+// ResolveID locates the specified slot entry and returns the id of the entity it resolved to.
+func (s Slots) ResolveID(slotName string) string {
+	return s[slotName].ResolveID()
+}
+
+// Resolutions contains the entity resolution results for a slot value from each authority that Alexa
+// consulted. There is one authority for the static values in your interaction model and another for
+// any dynamic entities you have sent to Alexa for the current user.
+//
+// See: https://developer.amazon.com/en-US/docs/alexa/custom-skills/entity-resolution.html
+type Resolutions struct {
+	ResolutionPerAuthority []ResolutionAuthority `json:"resolutionsPerAuthority"`
+}
 
-	resolution := resolutions[0]
-	if resolution.Status.Code == resolutionSuccessCode && len(resolution.Values) > 0 {
-		return resolution.Values[0].Value.Name
+// ResolveID returns the id of the best match, preferring dynamic entities over static values.
+func (r Resolutions) ResolveID() string {
+	match, _ := r.best()
+	return match.ID
+}
+
+// Matches returns every entity matched by every successful authority, dynamic entities first.
+func (r Resolutions) Matches() []ResolvedValue {
+	var dynamicMatches, staticMatches []ResolvedValue
+	for _, authority := range r.ResolutionPerAuthority {
+		if !authority.Matched() {
+			continue
+		}
+		for _, value := range authority.Values {
+			match := ResolvedValue{
+				Name:      value.Value.Name,
+				ID:        value.Value.ID,
+				Authority: authority.Authority,
+				Dynamic:   authority.Dynamic(),
+			}
+			if match.Dynamic {
+				dynamicMatches = append(dynamicMatches, match)
+			} else {
+				staticMatches = append(staticMatches, match)
+			}
+		}
 	}
-	return ""
+	return append(dynamicMatches, staticMatches...)
+}
+
+// best picks the match that we should use when resolving the slot to a single value.
+func (r Resolutions) best() (ResolvedValue, bool) {
+	matches := r.Matches()
+	if len(matches) == 0 {
+		return ResolvedValue{}, false
+	}
+	return matches[0], true
 }
 
-type resolutionPerAuthority struct {
+// ResolutionAuthority contains the results of resolving a slot value against one source of entities.
+type ResolutionAuthority struct {
 	Authority string             `json:"authority"`
-	Status    resolutionStatus   `json:"status"`
-	Values    []resolutionValues `json:"values"`
+	Status    ResolutionStatus   `json:"status"`
+	Values    []ResolutionValues `json:"values"`
 }
 
-type resolutionStatus struct {
+// Dynamic returns true if this authority represents your dynamic entities rather than the static
+// values from your interaction model.
+func (authority ResolutionAuthority) Dynamic() bool {
+	return strings.HasPrefix(authority.Authority, dynamicAuthorityPrefix)
+}
+
+// Matched returns true if the authority successfully matched what the user said to an entity.
+func (authority ResolutionAuthority) Matched() bool {
+	return authority.Status.Code == ResolutionSuccessMatch
+}
+
+// ResolutionStatus indicates whether entity resolution succeeded (e.g. ResolutionSuccessMatch).
+type ResolutionStatus struct {
 	Code string `json:"code"`
 }
 
-type resolutionValues struct {
-	Value resolutionValue `json:"value"`
+// ResolutionValues wraps a single matched entity (this mirrors the structure of the Alexa JSON).
+type ResolutionValues struct {
+	Value ResolutionValue `json:"value"`
 }
 
-type resolutionValue struct {
+// ResolutionValue is an entity that the authority matched, including the id you assigned to it.
+type ResolutionValue struct {
 	Name string `json:"name"`
 	ID   string `json:"id"`
 }
 
+// ResolvedValue is a flattened view of one entity that a slot value matched.
+type ResolvedValue struct {
+	// Name is the canonical value of the entity.
+	Name string
+	// ID is the id you assigned to the entity in your model or dynamic entities.
+	ID string
+	// Authority is the full name of the authority that matched the entity.
+	Authority string
+	// Dynamic is true when the entity came from your dynamic entities.
+	Dynamic bool
+}
+
 // NewResolvedSlot is mainly used for faking test data to create a slot that
 // has an uttered value as well as a resolution value.
 func NewResolvedSlot(name, value, resolvedValue string) Slot {
 	return Slot{
 		Name:  name,
 		Value: value,
-		Resolutions: resolutions{
-			ResolutionPerAuthority: resolutionAuthorities{
-				resolutionPerAuthority{
-					Status: resolutionStatus{
-						Code: resolutionSuccessCode,
+		Resolutions: Resolutions{
+			ResolutionPerAuthority: []ResolutionAuthority{
+				{
+					Status: ResolutionStatus{
+						Code: ResolutionSuccessMatch,
 					},
-					Values: []resolutionValues{
-						{Value: resolutionValue{Name: resolvedValue}},
+					Values: []ResolutionValues{
+						{Value: ResolutionValue{Name: resolvedValue}},
 					},
 				},
 			},
@@ -122,3 +323,16 @@ func NewSlots(values ...Slot) Slots {
 	}
 	return slots
 }
+
+// NewListSlot is used primarily for faking data in tests. It creates a multi-value slot where
+// the user said each of the given values.
+func NewListSlot(name string, values ...string) Slot {
+	slotValue := SlotValue{Type: SlotValueTypeList}
+	for _, value := range values {
+		slotValue.Values = append(slotValue.Values, SlotValue{Type: SlotValueTypeSimple, Value: value})
+	}
+	return Slot{
+		Name:      name,
+		SlotValue: &slotValue,
+	}
+}
@@ -79,3 +79,54 @@ func (suite SlotsSuite) TestResolve() {
 	suite.Equal("soda", slots.Resolve("beverage"),
 		"Valid slots w/ resolutions should delegate to the resolution authority")
 }
+
+func (suite SlotsSuite) TestValues() {
+	slots := golexa.NewSlots(
+		golexa.NewSlot("name", "Foo"),
+		golexa.NewSlot("blank", ""),
+		golexa.NewResolvedSlot("beverage", "pop", "soda"),
+		golexa.NewListSlot("items", "eggs", "milk", "bread"),
+	)
+
+	suite.Len(slots["blank"].Values(), 0,
+		"Blank slots should have no values")
+	suite.Len(slots["missing"].Values(), 0,
+		"Non-existent slots should have no values")
+
+	values := slots["beverage"].Values()
+	suite.Require().Len(values, 1,
+		"Single value slots should have exactly one value")
+	suite.Equal("pop", values[0].Value,
+		"Single value slots should include the spoken value")
+	suite.Equal("soda", values[0].Resolve(),
+		"Single value slots should keep their resolutions")
+
+	values = slots["items"].Values()
+	suite.Require().Len(values, 3,
+		"Multi-value slots should have one value per thing the user said")
+	suite.Equal("milk", values[1].Resolve(),
+		"Multi-value slots should resolve each value individually")
+	suite.Equal("eggs, milk, bread", slots.Resolve("items"),
+		"Resolving a multi-value slot should include all of the values")
+	suite.Equal([]string{"eggs", "milk", "bread"}, slots.ResolveValues("items"),
+		"Should resolve each value of a multi-value slot")
+	suite.Equal([]string{"soda"}, slots.ResolveValues("beverage"),
+		"Should resolve the one value of a single value slot")
+}
+
+func (suite SlotsSuite) TestClone_MultiValue() {
+	slots := golexa.NewSlots(golexa.NewListSlot("items", "eggs", "milk"))
+	slots["items"].SlotValue.Values[1] = golexa.NewResolvedSlot("", "2%", "milk").Values()[0]
+
+	clone := slots.Clone()
+	suite.Require().NotNil(clone["items"].SlotValue,
+		"Cloned multi-value slots should keep their 'slotValue'")
+	suite.Equal(golexa.SlotValueTypeList, clone["items"].SlotValue.Type,
+		"Cloned multi-value slots should still be lists")
+	suite.Equal("", clone["items"].Value,
+		"Cloned multi-value slots should not have a top-level value")
+	suite.Equal([]string{"eggs", "milk"}, clone.ResolveValues("items"),
+		"Cloned multi-value slots should use the *resolved* value of each item")
+	suite.Equal("milk", clone["items"].SlotValue.Values[1].Value,
+		"Cloned multi-value slots should use the *resolved* value of each item")
+}
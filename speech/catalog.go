@@ -0,0 +1,137 @@
+package speech
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// NewCatalog creates an empty catalog that you can register your templates with.
+func NewCatalog() *Catalog {
+	return &Catalog{
+		templates: map[string]Template{},
+		used:      map[string]bool{},
+		missing:   map[string]bool{},
+	}
+}
+
+// Catalog keeps all of your templates in one place, keyed by a message ID like "todo.add.success".
+// Rather than building a struct full of template fields for each of your services, register your
+// templates once and share the catalog across all of your handlers (see `Skill.Catalog()` and
+// `Response.SpeakMessage()`). It also keeps track of which messages you actually use, so you can
+// clean out dead ones (`Unused()`) and find typos in your message IDs (`Missing()`).
+//
+// A catalog is safe to use from multiple goroutines.
+type Catalog struct {
+	mutex     sync.RWMutex
+	templates map[string]Template
+	used      map[string]bool
+	missing   map[string]bool
+}
+
+// Add registers the template under the given message ID, replacing any other template w/ that ID.
+func (c *Catalog) Add(id string, template Template) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.templates[id] = template
+}
+
+// AddMessages registers a template for every message that you loaded from your message files. The
+// options (e.g. `WithFunc()`) are applied to every one of the templates.
+func (c *Catalog) AddMessages(messages Messages, options ...TemplateOption) error {
+	for _, id := range messages.IDs() {
+		template, err := messages.Template(id, options...)
+		if err != nil {
+			return err
+		}
+		c.Add(id, template)
+	}
+	return nil
+}
+
+// Template looks up the template w/ the given message ID.
+func (c *Catalog) Template(id string) (Template, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	template, ok := c.templates[id]
+	if ok {
+		c.used[id] = true
+	} else {
+		c.missing[id] = true
+	}
+	return template, ok
+}
+
+// Eval evaluates the template w/ the given message ID. It fails if there's no such template.
+func (c *Catalog) Eval(id string, ctx TemplateContext) (string, error) {
+	template, ok := c.Template(id)
+	if !ok {
+		return "", fmt.Errorf("speech: unknown message '%s'", id)
+	}
+	return template.Eval(ctx)
+}
+
+// IDs returns the message IDs of all of the registered templates in alphabetical order.
+func (c *Catalog) IDs() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	ids := make([]string, 0, len(c.templates))
+	for id := range c.templates {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Unused returns the message IDs of all of the registered templates that nobody has looked up yet.
+// Run this at the end of your test suite to find messages that you can delete.
+func (c *Catalog) Unused() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	var ids []string
+	for id := range c.templates {
+		if !c.used[id] {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Missing returns all of the message IDs that somebody tried to look up that were never registered.
+// These are most likely typos in your handlers.
+func (c *Catalog) Missing() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	var ids []string
+	for id := range c.missing {
+		if _, ok := c.templates[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Validate makes sure that the catalog has templates for all of the given message IDs. You should call
+// this when your skill starts up w/ the IDs your handlers use.
+func (c *Catalog) Validate(ids ...string) error {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	var missing []string
+	for _, id := range ids {
+		if _, ok := c.templates[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("speech: catalog is missing messages: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
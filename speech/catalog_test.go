@@ -0,0 +1,59 @@
+package speech_test
+
+import (
+	"testing"
+
+	"github.com/robsignorelli/golexa/speech"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/text/language"
+)
+
+func TestCatalogSuite(t *testing.T) {
+	suite.Run(t, new(CatalogSuite))
+}
+
+type CatalogSuite struct {
+	suite.Suite
+}
+
+func (suite CatalogSuite) TestCatalog() {
+	catalog := speech.NewCatalog()
+	catalog.Add("hello", speech.NewTemplate("Hello {{.Value}}"))
+	catalog.Add("goodbye", speech.NewTemplate("Goodbye"))
+	catalog.Add("hello", speech.NewTemplate("Hi {{.Value}}"))
+
+	suite.Equal([]string{"goodbye", "hello"}, catalog.IDs(), "Should list the registered messages")
+	suite.Equal([]string{"goodbye", "hello"}, catalog.Unused(), "Should start w/ all messages unused")
+
+	output, err := catalog.Eval("hello", speech.TemplateContext{Value: "Rob"})
+	suite.Require().NoError(err, "Should evaluate registered messages")
+	suite.Equal("Hi Rob", output, "Should replace messages w/ the same ID")
+	suite.Equal([]string{"goodbye"}, catalog.Unused(), "Should track used messages")
+
+	_, err = catalog.Eval("helo", speech.TemplateContext{})
+	suite.Error(err, "Should fail to evaluate unknown messages")
+	_, ok := catalog.Template("farewell")
+	suite.False(ok, "Should not find unknown messages")
+	suite.Equal([]string{"farewell", "helo"}, catalog.Missing(), "Should track missing messages")
+
+	catalog.Add("farewell", speech.NewTemplate("Farewell"))
+	suite.Equal([]string{"helo"}, catalog.Missing(), "Should not report messages that were added later")
+
+	suite.NoError(catalog.Validate("hello", "goodbye"), "Should validate registered messages")
+	err = catalog.Validate("hello", "nope", "nada")
+	suite.Require().Error(err, "Should fail validation for missing messages")
+	suite.Equal("speech: catalog is missing messages: nope, nada", err.Error(), "Should list the missing messages")
+}
+
+func (suite CatalogSuite) TestAddMessages() {
+	messages, err := speech.LoadMessages("testdata/messages")
+	suite.Require().NoError(err, "Should load the message files")
+
+	catalog := speech.NewCatalog()
+	suite.Require().NoError(catalog.AddMessages(messages), "Should add all of the messages")
+	suite.Equal(messages.IDs(), catalog.IDs(), "Should register a template for each message")
+
+	output, err := catalog.Eval("todo.add.success", speech.TemplateContext{Language: language.Spanish, Value: "leche"})
+	suite.Require().NoError(err, "Should evaluate loaded messages")
+	suite.Equal(`Bueno. He agregado "leche" a su lista.`, output, "Should include translations")
+}
@@ -0,0 +1,225 @@
+package speech
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/robsignorelli/golexa/ssml"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// formatFuncs are the functions that every template gets for free. They format values based on the
+// language of the request, so "{{number .Value}}" is "1,234.5" in English, but "1.234,5" in German.
+//
+//	number    - 1234.5 -> "1,234.5"
+//	ordinal   - 3 -> "third" (using SSML say-as)
+//	currency  - 12.5 "USD" -> "$12.50"
+//	date      - a time.Time -> "October eighteenth" (using SSML say-as)
+//	time      - a time.Time -> "3:30 PM" or "15:30" depending on the locale
+//	duration  - a time.Duration -> "2 hours and 5 minutes"
+//	list      - a slice -> "eggs, milk, and bread" or "huevos, leche y pan"
+//	plural    - 1 "one" "# item" "other" "# items" -> "1 item"
+//	select    - "female" "female" "she" "other" "they" -> "she"
+//	upper     - "hello" -> "HELLO"
+//	lower     - "HELLO" -> "hello"
+func formatFuncs(lang language.Tag) template.FuncMap {
+	printer := message.NewPrinter(lang)
+	return template.FuncMap{
+		"number": func(value interface{}) string {
+			if text, ok := numeric(value).(string); ok {
+				return text
+			}
+			return printer.Sprint(number.Decimal(numeric(value)))
+		},
+		"ordinal": func(value interface{}) SSML {
+			return SSML(`<say-as interpret-as="ordinal">` + ssml.Escape(fmt.Sprint(numeric(value))) + `</say-as>`)
+		},
+		"currency": func(amount interface{}, code string) (string, error) {
+			return formatCurrency(printer, lang, amount, code)
+		},
+		"date": func(date time.Time) SSML {
+			return SSML(`<say-as interpret-as="date">` + date.Format("20060102") + `</say-as>`)
+		},
+		"time": func(timeOfDay time.Time) string {
+			if uses12HourClock(lang) {
+				return timeOfDay.Format("3:04 PM")
+			}
+			return timeOfDay.Format("15:04")
+		},
+		"duration": func(duration time.Duration) string {
+			return formatDuration(printer, lang, duration)
+		},
+		"list": func(items interface{}) string {
+			return formatList(lang, toStrings(items))
+		},
+		"plural": func(count interface{}, cases ...string) (string, error) {
+			return formatPlural(printer, lang, count, cases...)
+		},
+		"select": formatSelect,
+		"upper":  cases.Upper(lang).String,
+		"lower":  cases.Lower(lang).String,
+	}
+}
+
+// numeric converts strings like slot values into numbers so that you can format them. Anything else
+// is returned as-is for the printer to deal w/.
+func numeric(value interface{}) interface{} {
+	text, ok := value.(string)
+	if !ok {
+		return value
+	}
+	if i, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(text, 64); err == nil {
+		return f
+	}
+	return value
+}
+
+func formatCurrency(printer *message.Printer, lang language.Tag, amount interface{}, code string) (string, error) {
+	unit, err := currency.ParseISO(code)
+	if err != nil {
+		return "", fmt.Errorf("currency: %v", err)
+	}
+	scale, _ := currency.Standard.Rounding(unit)
+	value := printer.Sprint(number.Decimal(numeric(amount), number.Scale(scale)))
+	symbol := printer.Sprint(currency.NarrowSymbol(unit))
+
+	// Most of the European languages put the symbol after the amount (e.g. "12,50 €").
+	base, _ := lang.Base()
+	region, _ := lang.Region()
+	switch {
+	case base.String() == "de", base.String() == "fr", base.String() == "it", base.String() == "es" && region.String() == "ES":
+		return value + " " + symbol, nil
+	default:
+		return symbol + value, nil
+	}
+}
+
+// uses12HourClock is true for the Alexa locales that normally say times like "3:30 PM".
+func uses12HourClock(lang language.Tag) bool {
+	region, _ := lang.Region()
+	switch region.String() {
+	case "US", "CA", "AU", "IN":
+		return true
+	default:
+		return false
+	}
+}
+
+// durationUnit is the singular/plural name of a unit of time in a given language.
+type durationUnit struct {
+	one   string
+	other string
+}
+
+// durationUnits are the names of days, hours, minutes, and seconds (in that order) for each of the
+// languages Alexa supports. Anything else uses English.
+var durationUnits = map[string][4]durationUnit{
+	"en": {{"day", "days"}, {"hour", "hours"}, {"minute", "minutes"}, {"second", "seconds"}},
+	"es": {{"día", "días"}, {"hora", "horas"}, {"minuto", "minutos"}, {"segundo", "segundos"}},
+	"fr": {{"jour", "jours"}, {"heure", "heures"}, {"minute", "minutes"}, {"seconde", "secondes"}},
+	"de": {{"Tag", "Tage"}, {"Stunde", "Stunden"}, {"Minute", "Minuten"}, {"Sekunde", "Sekunden"}},
+	"it": {{"giorno", "giorni"}, {"ora", "ore"}, {"minuto", "minuti"}, {"secondo", "secondi"}},
+	"pt": {{"dia", "dias"}, {"hora", "horas"}, {"minuto", "minutos"}, {"segundo", "segundos"}},
+	"hi": {{"दिन", "दिन"}, {"घंटा", "घंटे"}, {"मिनट", "मिनट"}, {"सेकंड", "सेकंड"}},
+	"ja": {{"日", "日"}, {"時間", "時間"}, {"分", "分"}, {"秒", "秒"}},
+}
+
+func formatDuration(printer *message.Printer, lang language.Tag, duration time.Duration) string {
+	base, _ := lang.Base()
+	units, ok := durationUnits[base.String()]
+	if !ok {
+		units = durationUnits["en"]
+	}
+
+	// Japanese doesn't put a space between the number and the unit ("5分").
+	separator := " "
+	if base.String() == "ja" {
+		separator = ""
+	}
+
+	seconds := int64(duration / time.Second)
+	amounts := []int64{seconds / 86400, seconds % 86400 / 3600, seconds % 3600 / 60, seconds % 60}
+	var parts []string
+	for i, amount := range amounts {
+		if amount == 0 {
+			continue
+		}
+		name := units[i].other
+		if amount == 1 {
+			name = units[i].one
+		}
+		parts = append(parts, printer.Sprint(number.Decimal(amount))+separator+name)
+	}
+	if len(parts) == 0 {
+		return "0" + separator + units[3].other
+	}
+	return formatList(lang, parts)
+}
+
+// listConjunctions is the word that goes before the last item in a list for each language.
+var listConjunctions = map[string]string{
+	"en": "and",
+	"es": "y",
+	"fr": "et",
+	"de": "und",
+	"it": "e",
+	"pt": "e",
+	"hi": "और",
+}
+
+func formatList(lang language.Tag, items []string) string {
+	base, _ := lang.Base()
+	if base.String() == "ja" {
+		return strings.Join(items, "、")
+	}
+
+	conjunction, ok := listConjunctions[base.String()]
+	if !ok {
+		conjunction = listConjunctions["en"]
+	}
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	case 2:
+		return items[0] + " " + conjunction + " " + items[1]
+	}
+
+	// Americans love their Oxford comma; nobody else seems to. Plain "en" counts as American, too.
+	last := " " + conjunction + " "
+	if region, _ := lang.Region(); base.String() == "en" && region.String() == "US" {
+		last = ", " + conjunction + " "
+	}
+	return strings.Join(items[:len(items)-1], ", ") + last + items[len(items)-1]
+}
+
+// toStrings converts any slice/array into the string values of its elements.
+func toStrings(items interface{}) []string {
+	if values, ok := items.([]string); ok {
+		return values
+	}
+	value := reflect.ValueOf(items)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		if items == nil {
+			return nil
+		}
+		return []string{fmt.Sprint(items)}
+	}
+	results := make([]string, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		results[i] = fmt.Sprint(value.Index(i).Interface())
+	}
+	return results
+}
@@ -0,0 +1,106 @@
+package speech_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robsignorelli/golexa/speech"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/text/language"
+)
+
+func TestFormatSuite(t *testing.T) {
+	suite.Run(t, new(FormatSuite))
+}
+
+type FormatSuite struct {
+	suite.Suite
+}
+
+func (suite FormatSuite) eval(text string, locale string, value interface{}) string {
+	output, err := speech.NewTemplate(text).Eval(speech.TemplateContext{
+		Language: language.MustParse(locale),
+		Value:    value,
+	})
+	suite.Require().NoError(err, "Should evaluate the template: "+text)
+	return output
+}
+
+func (suite FormatSuite) TestNumbers() {
+	suite.Equal("1,234.5", suite.eval("{{number .Value}}", "en-US", 1234.5), "Should format numbers")
+	suite.Equal("1.234,5", suite.eval("{{number .Value}}", "de-DE", 1234.5), "Should format numbers for the request's language")
+	suite.Equal("12,34,567", suite.eval("{{number .Value}}", "hi-IN", "1234567"), "Should format numeric strings")
+	suite.Equal("abc", suite.eval("{{number .Value}}", "en-US", "abc"), "Should leave non-numeric strings alone")
+
+	suite.Equal(`<say-as interpret-as="ordinal">3</say-as>`, suite.eval("{{ordinal .Value}}", "en-US", 3),
+		"Should speak ordinals using say-as")
+	suite.Equal(`<say-as interpret-as="ordinal">Tom &amp; Jerry &lt;x&gt;</say-as>`,
+		suite.eval("{{ordinal .Value}}", "en-US", "Tom & Jerry <x>"), "Should escape non-numeric ordinals")
+
+	suite.Equal("$12.50", suite.eval(`{{currency .Value "USD"}}`, "en-US", 12.5), "Should format currency")
+	suite.Equal("1.234,50 €", suite.eval(`{{currency .Value "EUR"}}`, "de-DE", 1234.5), "Should format currency for the request's language")
+	suite.Equal("￥1,235", suite.eval(`{{currency .Value "JPY"}}`, "ja-JP", 1234.6), "Should use the currency's scale")
+	_, err := speech.NewTemplate(`{{currency .Value "XYZ1"}}`).Eval(speech.TemplateContext{Value: 1})
+	suite.Error(err, "Should fail on invalid currency codes")
+}
+
+func (suite FormatSuite) TestDatesAndTimes() {
+	when := time.Date(2026, 10, 18, 15, 30, 0, 0, time.UTC)
+	suite.Equal(`<say-as interpret-as="date">20261018</say-as>`, suite.eval("{{date .Value}}", "en-US", when),
+		"Should speak dates using say-as")
+	suite.Equal("3:30 PM", suite.eval("{{time .Value}}", "en-US", when), "Should use a 12-hour clock in the US")
+	suite.Equal("15:30", suite.eval("{{time .Value}}", "de-DE", when), "Should use a 24-hour clock in Germany")
+
+	duration := 26*time.Hour + 5*time.Minute + time.Second
+	suite.Equal("1 day, 2 hours, 5 minutes, and 1 second", suite.eval("{{duration .Value}}", "en-US", duration),
+		"Should speak durations")
+	suite.Equal("5 minutos y 30 segundos", suite.eval("{{duration .Value}}", "es-MX", 5*time.Minute+30*time.Second),
+		"Should speak durations in the request's language")
+	suite.Equal("5分", suite.eval("{{duration .Value}}", "ja-JP", 5*time.Minute), "Should not add spaces in Japanese")
+	suite.Equal("0 seconds", suite.eval("{{duration .Value}}", "en-US", time.Duration(0)), "Should speak empty durations")
+}
+
+func (suite FormatSuite) TestLists() {
+	items := []string{"eggs", "milk", "bread"}
+	suite.Equal("eggs, milk, and bread", suite.eval("{{list .Value}}", "en-US", items), "Should use the Oxford comma in the US")
+	suite.Equal("eggs, milk, and bread", suite.eval("{{list .Value}}", "en", items), "Should use the Oxford comma for plain English")
+	suite.Equal("eggs, milk, and bread", suite.eval("{{list .Value}}", "en-US-u-ca-gregory", items),
+		"Should use the Oxford comma for US English w/ extensions")
+	suite.Equal("eggs, milk and bread", suite.eval("{{list .Value}}", "en-GB", items), "Should skip the Oxford comma elsewhere")
+	suite.Equal("huevos, leche y pan", suite.eval("{{list .Value}}", "es-MX", []string{"huevos", "leche", "pan"}),
+		"Should use the language's conjunction")
+	suite.Equal("卵、牛乳", suite.eval("{{list .Value}}", "ja-JP", []string{"卵", "牛乳"}), "Should join Japanese lists")
+	suite.Equal("1 and 2", suite.eval("{{list .Value}}", "en-US", []int{1, 2}), "Should support any type of slice")
+	suite.Equal("Tom &amp; Jerry", suite.eval("{{list .Value}}", "en-US", []string{"Tom & Jerry"}), "Should escape list items")
+	suite.Equal("", suite.eval("{{list .Value}}", "en-US", []string{}), "Should support empty lists")
+}
+
+func (suite FormatSuite) TestOverrides() {
+	t := speech.NewTemplate("{{upper .Value}}", speech.WithFunc("upper", func(string) string { return "custom" }))
+	output, err := t.Eval(speech.TemplateContext{Language: language.German, Value: "hi"})
+	suite.Require().NoError(err, "Should evaluate the template")
+	suite.Equal("custom", output, "Should let your functions override the built-in ones")
+
+	suite.Equal("HELLO", suite.eval("{{upper .Value}}", "en-US", "hello"), "Should have an upper function")
+	suite.Equal("hello", suite.eval("{{lower .Value}}", "en-US", "HELLO"), "Should have a lower function")
+}
+
+func (suite FormatSuite) TestLocalizedVariants() {
+	t := speech.NewTemplate("A {{number .Value}}",
+		speech.WithVariants(language.AmericanEnglish, "B {{number .Value}}"),
+		speech.WithSelection(speech.RoundRobin))
+
+	eval := func(locale string, state speech.VariantState) string {
+		output, err := t.Eval(speech.TemplateContext{Language: language.MustParse(locale), Value: 1234.5, Variants: state})
+		suite.Require().NoError(err, "Should evaluate the template")
+		return output
+	}
+
+	gb, de := memoryState{}, memoryState{}
+	for i := 0; i < 2; i++ {
+		suite.Equal("A 1,234.5", eval("en-GB", gb), "Should localize the first variant every time")
+		suite.Equal("A 1.234,5", eval("de-DE", de), "Should localize each language separately")
+		suite.Equal("B 1,234.5", eval("en-GB", gb), "Should localize the second variant every time")
+		suite.Equal("B 1.234,5", eval("de-DE", de), "Should localize each language's variants separately")
+	}
+}
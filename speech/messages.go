@@ -0,0 +1,309 @@
+package speech
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v2"
+)
+
+// LoadMessages reads all of the translations for your templates from a directory w/ one file per
+// locale, such as "en-US.yaml", "es.json", or "fr-FR.toml". Each file maps a message ID to its text
+// (or a list of texts to use them as variants). Nested maps are flattened using dots, so these
+// two YAML files define the same "todo.add.success" message:
+//
+//	todo.add.success: Okay. I have added "{{.Value}}" to your list.
+//
+//	todo:
+//	  add:
+//	    success: Okay. I have added "{{.Value}}" to your list.
+//
+// This lets you hand the files off to translators rather than burying your speech in Go code. Any
+// "<locale>.missing.<format>" files that "golexa messages missing" wrote for them are ignored.
+func LoadMessages(dir string) (Messages, error) {
+	return LoadMessagesFS(http.Dir(dir), "/")
+}
+
+// LoadMessagesFS works just like `LoadMessages`, but reads the files from the given directory of
+// any file system, so you can load messages that you compiled into your binary.
+func LoadMessagesFS(fileSystem http.FileSystem, dir string) (Messages, error) {
+	messages := Messages{translations: map[string]map[language.Tag][]string{}}
+
+	folder, err := fileSystem.Open(dir)
+	if err != nil {
+		return messages, fmt.Errorf("speech: unable to open messages: %v", err)
+	}
+	defer folder.Close()
+	files, err := folder.Readdir(-1)
+	if err != nil {
+		return messages, fmt.Errorf("speech: unable to read messages: %v", err)
+	}
+
+	for _, info := range files {
+		ext := path.Ext(info.Name())
+		if info.IsDir() || decoders[ext] == nil || isMissingFile(info.Name()) {
+			continue
+		}
+		lang, err := language.Parse(strings.TrimSuffix(info.Name(), ext))
+		if err != nil {
+			return messages, fmt.Errorf("speech: file '%s' is not named after a locale: %v", info.Name(), err)
+		}
+		if err := messages.load(fileSystem, path.Join(dir, info.Name()), lang, decoders[ext]); err != nil {
+			return messages, err
+		}
+	}
+	return messages, nil
+}
+
+// isMissingFile returns true for the "<locale>.missing.<format>" files that "golexa messages missing"
+// writes for your translators. They only contain English text, so they don't belong in the locale.
+func isMissingFile(name string) bool {
+	return strings.HasSuffix(strings.TrimSuffix(name, path.Ext(name)), ".missing")
+}
+
+// decoders unmarshal each of the file types we support into a generic map.
+var decoders = map[string]func([]byte, *map[string]interface{}) error{
+	".json": func(data []byte, out *map[string]interface{}) error { return json.Unmarshal(data, out) },
+	".yaml": func(data []byte, out *map[string]interface{}) error { return yaml.Unmarshal(data, out) },
+	".yml":  func(data []byte, out *map[string]interface{}) error { return yaml.Unmarshal(data, out) },
+	".toml": func(data []byte, out *map[string]interface{}) error { return toml.Unmarshal(data, out) },
+}
+
+// Messages contains the text of all of your templates in every locale, keyed by message ID.
+type Messages struct {
+	translations map[string]map[language.Tag][]string
+	locales      []language.Tag
+}
+
+// IDs returns all of the message IDs in alphabetical order.
+func (m Messages) IDs() []string {
+	ids := make([]string, 0, len(m.translations))
+	for id := range m.translations {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Locales returns all of the locales that we loaded files for.
+func (m Messages) Locales() []language.Tag {
+	return append([]language.Tag(nil), m.locales...)
+}
+
+// Text returns the text (and any variants) of the message defined in exactly the given locale. It does
+// not fall back to other languages.
+func (m Messages) Text(lang language.Tag, id string) ([]string, bool) {
+	texts, ok := m.translations[id][lang]
+	return texts, ok
+}
+
+// Template creates a speech template for the message w/ all of its translations. Requests in other
+// languages fall back the same way they do when you use `WithTranslation()`, so an "es-MX" request
+// uses the "es" text if there's no "es-MX" text. Every message must have English text, either in
+// your "en-US" or "en" file. You can provide additional options like `WithFunc()` or `WithSelection()`.
+// The template remembers its variants using the message id, so it's fine to create it per request.
+func (m Messages) Template(id string, options ...TemplateOption) (t Template, err error) {
+	translations, ok := m.translations[id]
+	if !ok {
+		return t, fmt.Errorf("speech: unknown message '%s'", id)
+	}
+
+	englishLang := language.AmericanEnglish
+	english := translations[englishLang]
+	if len(english) == 0 {
+		englishLang = language.English
+		english = translations[englishLang]
+	}
+	if len(english) == 0 {
+		return t, fmt.Errorf("speech: message '%s' does not have any English text", id)
+	}
+
+	// Give it a stable key so that you can create the template for a message on every request and
+	// still remember its variants. Any WithID() you provide comes later, so it still wins.
+	options = append([]TemplateOption{WithID("message:" + id)}, options...)
+	options = append(options, WithVariants(language.AmericanEnglish, english[1:]...))
+	for lang, texts := range translations {
+		if lang == englishLang || len(texts) == 0 {
+			continue
+		}
+		options = append(options, WithTranslation(lang, texts[0]), WithVariants(lang, texts[1:]...))
+	}
+
+	// NewTemplate panics on bad templates since they're normally hard-coded, but these came from a
+	// file, so just let the caller know which one is busted.
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("speech: invalid message '%s': %v", id, recovered)
+		}
+	}()
+	return NewTemplate(english[0], options...), nil
+}
+
+// MustTemplate works just like `Template`, but panics if there's an error.
+func (m Messages) MustTemplate(id string, options ...TemplateOption) Template {
+	t, err := m.Template(id, options...)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Missing finds all of the message IDs that don't have text in each of the given locales (or all
+// of the locales that we loaded if you don't provide any). A message is only missing if there's
+// no text for that locale or any of its parents, so "es-MX" is fine if your "es" file has it. English
+// locales can also fall back to your "en-US" text.
+func (m Messages) Missing(locales ...language.Tag) map[language.Tag][]string {
+	if len(locales) == 0 {
+		locales = m.locales
+	}
+
+	missing := map[language.Tag][]string{}
+	for _, lang := range locales {
+		for _, id := range m.IDs() {
+			if !m.has(id, lang) {
+				missing[lang] = append(missing[lang], id)
+			}
+		}
+	}
+	return missing
+}
+
+// Validate makes sure that every message has text in each of the given locales (or all of the locales
+// that we loaded if you don't provide any). You should call this when your skill starts up so you
+// don't find out about missing translations from your users.
+func (m Messages) Validate(locales ...language.Tag) error {
+	missing := m.Missing(locales...)
+	if len(missing) == 0 {
+		return nil
+	}
+	return MissingMessagesError{Messages: missing}
+}
+
+// MissingMessagesError is the error you get from `Messages.Validate()` when there are missing
+// translations. You can see exactly which message IDs are missing for each locale.
+type MissingMessagesError struct {
+	Messages map[language.Tag][]string
+}
+
+func (err MissingMessagesError) Error() string {
+	var locales []string
+	for lang, ids := range err.Messages {
+		locales = append(locales, fmt.Sprintf("%s (%s)", lang, strings.Join(ids, ", ")))
+	}
+	sort.Strings(locales)
+	return "speech: missing messages: " + strings.Join(locales, "; ")
+}
+
+func (m Messages) has(id string, lang language.Tag) bool {
+	english, _ := language.AmericanEnglish.Base()
+	base, _ := lang.Base()
+	for ; ; lang = lang.Parent() {
+		if len(m.translations[id][lang]) > 0 {
+			return true
+		}
+		if lang.IsRoot() {
+			return base == english && len(m.translations[id][language.AmericanEnglish]) > 0
+		}
+	}
+}
+
+func (m *Messages) load(fileSystem http.FileSystem, name string, lang language.Tag, decode func([]byte, *map[string]interface{}) error) error {
+	file, err := fileSystem.Open(name)
+	if err != nil {
+		return fmt.Errorf("speech: unable to open '%s': %v", name, err)
+	}
+	defer file.Close()
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		return fmt.Errorf("speech: unable to read '%s': %v", name, err)
+	}
+
+	values := map[string]interface{}{}
+	if err := decode(data, &values); err != nil {
+		return fmt.Errorf("speech: unable to parse '%s': %v", name, err)
+	}
+	flattened := map[string][]string{}
+	if err := flatten("", values, flattened); err != nil {
+		return fmt.Errorf("speech: invalid messages in '%s': %v", name, err)
+	}
+
+	for id, texts := range flattened {
+		if m.translations[id] == nil {
+			m.translations[id] = map[language.Tag][]string{}
+		}
+		if _, exists := m.translations[id][lang]; exists {
+			return fmt.Errorf("speech: message '%s' is defined more than once for %s", id, lang)
+		}
+		m.translations[id][lang] = texts
+	}
+	if !containsTag(m.locales, lang) {
+		m.locales = append(m.locales, lang)
+	}
+	return nil
+}
+
+// flatten converts the nested maps from a message file into dot-separated message IDs. Each
+// message is either a single string or a list of variants. Since "a.b" and a nested "a: {b}" both
+// flatten to the same ID, we fail rather than letting one quietly overwrite the other.
+func flatten(prefix string, value interface{}, out map[string][]string) error {
+	switch v := value.(type) {
+	case string:
+		if _, exists := out[prefix]; exists {
+			return fmt.Errorf("message '%s' is defined more than once", prefix)
+		}
+		out[prefix] = []string{v}
+	case []interface{}:
+		if _, exists := out[prefix]; exists {
+			return fmt.Errorf("message '%s' is defined more than once", prefix)
+		}
+		if len(v) == 0 {
+			return fmt.Errorf("message '%s' has an empty list of variants", prefix)
+		}
+		for _, variant := range v {
+			text, ok := variant.(string)
+			if !ok {
+				return fmt.Errorf("message '%s' has a variant that isn't text", prefix)
+			}
+			out[prefix] = append(out[prefix], text)
+		}
+	case map[string]interface{}:
+		for key, child := range v {
+			if err := flatten(joinID(prefix, key), child, out); err != nil {
+				return err
+			}
+		}
+	case map[interface{}]interface{}:
+		// YAML gives us these rather than string keys.
+		for key, child := range v {
+			if err := flatten(joinID(prefix, fmt.Sprint(key)), child, out); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("message '%s' must be text or a list of text", prefix)
+	}
+	return nil
+}
+
+func joinID(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func containsTag(tags []language.Tag, tag language.Tag) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package speech_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/robsignorelli/golexa/speech"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/text/language"
+)
+
+func TestMessagesSuite(t *testing.T) {
+	suite.Run(t, new(MessagesSuite))
+}
+
+type MessagesSuite struct {
+	suite.Suite
+}
+
+func (suite MessagesSuite) load() speech.Messages {
+	messages, err := speech.LoadMessages("testdata/messages")
+	suite.Require().NoError(err, "Should load the message files")
+	return messages
+}
+
+func (suite MessagesSuite) eval(t speech.Template, locale string, value interface{}) string {
+	output, err := t.Eval(speech.TemplateContext{Language: language.MustParse(locale), Value: value})
+	suite.Require().NoError(err, "Should evaluate the template")
+	return output
+}
+
+func (suite MessagesSuite) TestLoad() {
+	messages := suite.load()
+	suite.Equal([]string{"farewell", "greeting", "todo.add.success", "todo.list"}, messages.IDs(),
+		"Should flatten nested message IDs")
+	suite.ElementsMatch([]language.Tag{language.AmericanEnglish, language.Spanish, language.MustParse("fr-FR")}, messages.Locales(),
+		"Should load JSON, YAML, and TOML files")
+
+	texts, ok := messages.Text(language.AmericanEnglish, "greeting")
+	suite.True(ok, "Should find English text")
+	suite.Equal([]string{"Hello {{.Value}}", "Hi {{.Value}}"}, texts, "Should load lists as variants")
+	_, ok = messages.Text(language.MustParse("es-MX"), "greeting")
+	suite.False(ok, "Text should not fall back to other languages")
+	_, ok = messages.Text(language.MustParse("fr-FR"), "todo.list")
+	suite.False(ok, "Should ignore the '.missing' files we write for translators")
+
+	_, err := speech.LoadMessages("testdata/nope")
+	suite.Error(err, "Should fail when the directory doesn't exist")
+}
+
+func (suite MessagesSuite) TestTemplate() {
+	messages := suite.load()
+
+	t, err := messages.Template("todo.add.success")
+	suite.Require().NoError(err, "Should create templates for messages")
+	suite.Equal(`Okay. I have added "milk" to your list.`, suite.eval(t, "en-US", "milk"), "Should use the English text")
+	suite.Equal(`Bueno. He agregado "leche" a su lista.`, suite.eval(t, "es-MX", "leche"), "Should fall back to parent languages")
+	suite.Equal(`D'accord. J'ai ajouté « lait » à votre liste.`, suite.eval(t, "fr-FR", "lait"), "Should use TOML text")
+	suite.Equal(`Okay. I have added "Milch" to your list.`, suite.eval(t, "de-DE", "Milch"), "Should fall back to English")
+
+	t = messages.MustTemplate("greeting", speech.WithSelection(speech.RoundRobin))
+	ctx := speech.TemplateContext{Language: language.AmericanEnglish, Value: "Rob", Variants: memoryState{}}
+	first, _ := t.Eval(ctx)
+	second, _ := t.Eval(ctx)
+	suite.Equal([]string{"Hello Rob", "Hi Rob"}, []string{first, second}, "Should support variants and options")
+
+	_, err = messages.Template("nope")
+	suite.Error(err, "Should fail on unknown messages")
+	suite.Panics(func() { messages.MustTemplate("nope") }, "MustTemplate should panic on errors")
+}
+
+func (suite MessagesSuite) TestMissing() {
+	messages := suite.load()
+	spanish := language.MustParse("es-MX")
+	french := language.MustParse("fr-FR")
+
+	suite.Equal(map[language.Tag][]string{
+		spanish: {"farewell"},
+		french:  {"farewell", "todo.list"},
+	}, messages.Missing(language.AmericanEnglish, language.BritishEnglish, spanish, french),
+		"Should find missing messages, falling back to parent languages")
+
+	err := messages.Validate(spanish)
+	suite.Require().Error(err, "Should fail validation when messages are missing")
+	suite.Equal("speech: missing messages: es-MX (farewell)", err.Error(), "Should describe the missing messages")
+	suite.Equal(map[language.Tag][]string{spanish: {"farewell"}}, err.(speech.MissingMessagesError).Messages,
+		"Should expose the missing messages")
+
+	suite.NoError(messages.Validate(language.AmericanEnglish), "Should pass validation when nothing is missing")
+	suite.Error(messages.Validate(), "Should validate all loaded locales by default")
+}
+
+func (suite MessagesSuite) TestInvalidFiles() {
+	for name, content := range map[string]string{
+		"en-US.json":    `{"greeting": `,
+		"en-US.yaml":    "greeting: [1, 2]",
+		"en-US.toml":    "greeting = []",
+		"messages.json": `{"greeting": "hi"}`,
+		"en.yaml":       "todo.add: Added\ntodo:\n  add: Added again",
+		"en.json":       `{"todo.add": ["Added"], "todo": {"add": "Added again"}}`,
+	} {
+		dir, err := ioutil.TempDir("", "messages")
+		suite.Require().NoError(err, "Should create a temp dir")
+		defer os.RemoveAll(dir)
+		suite.Require().NoError(ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644), "Should write the file")
+
+		_, err = speech.LoadMessages(dir)
+		suite.Error(err, "Should fail to load invalid file: "+name)
+	}
+
+	dir, err := ioutil.TempDir("", "messages")
+	suite.Require().NoError(err, "Should create a temp dir")
+	defer os.RemoveAll(dir)
+	suite.Require().NoError(ioutil.WriteFile(filepath.Join(dir, "es.json"), []byte(`{"greeting": "Hola"}`), 0644), "Should write the file")
+	suite.Require().NoError(ioutil.WriteFile(filepath.Join(dir, "en.json"), []byte(`{"broken": "<blink>"}`), 0644), "Should write the file")
+	messages, err := speech.LoadMessages(dir)
+	suite.Require().NoError(err, "Should load the messages")
+	_, err = messages.Template("greeting")
+	suite.Error(err, "Should fail when messages don't have English text")
+	_, err = messages.Template("broken")
+	suite.Error(err, "Should fail when messages aren't valid templates")
+}
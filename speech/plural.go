@@ -0,0 +1,130 @@
+package speech
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// pluralForms are the CLDR plural categories you can use as cases in the "plural" function.
+var pluralForms = map[plural.Form]string{
+	plural.Zero:  "zero",
+	plural.One:   "one",
+	plural.Two:   "two",
+	plural.Few:   "few",
+	plural.Many:  "many",
+	plural.Other: "other",
+}
+
+// formatPlural picks the message for the count based on the CLDR plural rules of the language. The
+// cases are pairs of selectors and messages, just like an ICU plural format. A selector is either an
+// exact value like "=0" or one of the categories "zero", "one", "two", "few", "many", or "other". Any
+// "#" in the message is replaced w/ the formatted count.
+//
+//	{{plural (len .Value) "=0" "no items" "one" "# item" "other" "# items"}}
+func formatPlural(printer *message.Printer, lang language.Tag, count interface{}, cases ...string) (string, error) {
+	messages, err := pairs("plural", cases)
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := toFloat(numeric(count))
+	if !ok {
+		return "", fmt.Errorf("plural: '%v' is not a number", count)
+	}
+
+	formatted := printer.Sprint(number.Decimal(numeric(count)))
+	if message, ok := messages["="+strconv.FormatFloat(value, 'f', -1, 64)]; ok {
+		return strings.Replace(message, "#", formatted, -1), nil
+	}
+	if message, ok := messages[pluralForms[pluralForm(lang, value)]]; ok {
+		return strings.Replace(message, "#", formatted, -1), nil
+	}
+	if message, ok := messages["other"]; ok {
+		return strings.Replace(message, "#", formatted, -1), nil
+	}
+	return "", fmt.Errorf("plural: no case for %v and no 'other' case", count)
+}
+
+// pluralForm determines the CLDR plural category of the number in the given language. The rules need
+// the integer digits as well as the visible fraction digits (e.g. "1.50" is "1" and "50").
+func pluralForm(lang language.Tag, value float64) plural.Form {
+	text := strconv.FormatFloat(math.Abs(value), 'f', -1, 64)
+	integer, fraction := text, ""
+	if dot := strings.IndexByte(text, '.'); dot >= 0 {
+		integer, fraction = text[:dot], text[dot+1:]
+	}
+
+	i, _ := strconv.Atoi(integer)
+	f, _ := strconv.Atoi(fraction)
+	trimmed := strings.TrimRight(fraction, "0")
+	t, _ := strconv.Atoi(trimmed)
+	return plural.Cardinal.MatchPlural(lang, i, len(fraction), len(trimmed), f, t)
+}
+
+// formatSelect picks the message whose selector matches the value exactly (e.g. for gender), falling
+// back to the "other" case.
+//
+//	{{select .Value.Gender "female" "she" "male" "he" "other" "they"}}
+func formatSelect(value interface{}, cases ...string) (string, error) {
+	messages, err := pairs("select", cases)
+	if err != nil {
+		return "", err
+	}
+	if message, ok := messages[fmt.Sprint(value)]; ok {
+		return message, nil
+	}
+	if message, ok := messages["other"]; ok {
+		return message, nil
+	}
+	return "", fmt.Errorf("select: no case for '%v' and no 'other' case", value)
+}
+
+// pairs converts the alternating selector/message arguments into a lookup.
+func pairs(funcName string, cases []string) (map[string]string, error) {
+	if len(cases)%2 != 0 {
+		return nil, fmt.Errorf("%s: cases must be selector/message pairs", funcName)
+	}
+	messages := make(map[string]string, len(cases)/2)
+	for i := 0; i < len(cases); i += 2 {
+		messages[cases[i]] = cases[i+1]
+	}
+	return messages, nil
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,66 @@
+package speech_test
+
+import (
+	"testing"
+
+	"github.com/robsignorelli/golexa/speech"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/text/language"
+)
+
+func TestPluralSuite(t *testing.T) {
+	suite.Run(t, new(PluralSuite))
+}
+
+type PluralSuite struct {
+	suite.Suite
+}
+
+func (suite PluralSuite) eval(text string, locale string, value interface{}) string {
+	output, err := speech.NewTemplate(text).Eval(speech.TemplateContext{
+		Language: language.MustParse(locale),
+		Value:    value,
+	})
+	suite.Require().NoError(err, "Should evaluate the template: "+text)
+	return output
+}
+
+func (suite PluralSuite) TestPlural() {
+	items := `I found {{plural .Value "=0" "no items" "one" "# item" "other" "# items"}}.`
+	suite.Equal("I found no items.", suite.eval(items, "en-US", 0), "Should prefer exact matches")
+	suite.Equal("I found 1 item.", suite.eval(items, "en-US", 1), "Should use the 'one' form in English")
+	suite.Equal("I found 1,200 items.", suite.eval(items, "en-US", 1200), "Should format the number")
+	suite.Equal("I found 1.5 items.", suite.eval(items, "en-US", 1.5), "Should treat fractions as 'other' in English")
+	suite.Equal("I found 2 items.", suite.eval(items, "en-US", "2"), "Should support numeric strings")
+
+	articles := `{{plural .Value "one" "# article" "other" "# articles"}}`
+	suite.Equal("0 article", suite.eval(articles, "fr-FR", 0), "Should treat 0 as 'one' in French")
+	suite.Equal("1,5 article", suite.eval(articles, "fr-FR", 1.5), "Should treat 1.5 as 'one' in French")
+	suite.Equal("2 articles", suite.eval(articles, "fr-FR", 2), "Should treat 2 as 'other' in French")
+	suite.Equal("0 article", suite.eval(articles, "hi-IN", 0), "Should treat 0 as 'one' in Hindi")
+	suite.Equal("1 articles", suite.eval(articles, "ja-JP", 1), "Should only use 'other' in Japanese")
+
+	suite.Equal("3 few", suite.eval(`{{plural .Value "one" "# one" "few" "# few" "many" "# many"}}`, "ru", 3),
+		"Should support few/many forms")
+	suite.Equal("5 many", suite.eval(`{{plural .Value "one" "# one" "few" "# few" "many" "# many"}}`, "ru", 5),
+		"Should support few/many forms")
+
+	for _, text := range []string{
+		`{{plural .Value "one"}}`,
+		`{{plural .Value "one" "# item"}}`,
+	} {
+		_, err := speech.NewTemplate(text).Eval(speech.TemplateContext{Value: 2})
+		suite.Error(err, "Should fail when there's no matching case: "+text)
+	}
+	_, err := speech.NewTemplate(items).Eval(speech.TemplateContext{Value: "lots"})
+	suite.Error(err, "Should fail when the count isn't a number")
+}
+
+func (suite PluralSuite) TestSelect() {
+	pronoun := `{{select .Value "female" "she" "male" "he" "other" "they"}}`
+	suite.Equal("she", suite.eval(pronoun, "en-US", "female"), "Should select the matching case")
+	suite.Equal("they", suite.eval(pronoun, "en-US", "unknown"), "Should fall back to the other case")
+
+	_, err := speech.NewTemplate(`{{select .Value "female" "she"}}`).Eval(speech.TemplateContext{Value: "male"})
+	suite.Error(err, "Should fail when there's no matching case")
+}
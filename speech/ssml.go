@@ -0,0 +1,142 @@
+package speech
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/robsignorelli/golexa/ssml"
+	"golang.org/x/text/language"
+)
+
+// SSML is markup that you trust to be inserted into a template as-is rather than being escaped.
+// You can convert values in your template using the "ssml" function (e.g. "{{ssml .Value.Intro}}")
+// or just put SSML/ssml.Builder values in the data you evaluate the template with.
+type SSML string
+
+// escaperName is the function we tack onto the end of every action's pipeline. It's not a valid
+// identifier, so it won't collide w/ anything you register using `WithFunc()`.
+const escaperName = "_golexa_ssml_escaper"
+
+// parseSSML parses the template and rewrites every action (e.g. "{{.Value | upper}}") so that its
+// output is escaped as SSML text - this is how html/template works, but w/ XML escaping instead of
+// HTML's contextual escaping. It also validates the static markup of the template so that typos in
+// your tags are caught when your skill starts rather than when Alexa rejects a response.
+func parseSSML(lang language.Tag, text string, funcs template.FuncMap) (*template.Template, error) {
+	parsed, err := template.New(lang.String()).
+		Funcs(formatFuncs(lang)).
+		Funcs(template.FuncMap{"ssml": trustSSML}).
+		Funcs(funcs).
+		Funcs(template.FuncMap{escaperName: escapeSSML}).
+		Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range parsed.Templates() {
+		if t.Tree == nil {
+			continue
+		}
+		escapeActions(t.Tree, t.Tree.Root)
+
+		// Check the markup you'd get w/ the "if" branches as well as the "else" branches. Interpolated
+		// values are escaped text, so they can't affect whether or not the structure is valid.
+		for _, useElse := range []bool{false, true} {
+			skeleton := strings.Builder{}
+			writeSkeleton(&skeleton, t.Tree.Root, useElse)
+			if err := ssml.Validate(strings.TrimSpace(skeleton.String())); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return parsed, nil
+}
+
+// escapeActions appends the SSML escaper to every action that generates output.
+func escapeActions(tree *parse.Tree, node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			escapeActions(tree, child)
+		}
+	case *parse.ActionNode:
+		// Variable declarations/assignments like "{{$x := .Value}}" don't output anything.
+		if len(n.Pipe.Decl) > 0 {
+			return
+		}
+		escaper := parse.NewIdentifier(escaperName).SetTree(tree).SetPos(n.Pos)
+		n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      n.Pos,
+			Args:     []parse.Node{escaper},
+		})
+	case *parse.IfNode:
+		escapeActions(tree, n.List)
+		escapeActions(tree, n.ElseList)
+	case *parse.RangeNode:
+		escapeActions(tree, n.List)
+		escapeActions(tree, n.ElseList)
+	case *parse.WithNode:
+		escapeActions(tree, n.List)
+		escapeActions(tree, n.ElseList)
+	}
+}
+
+// writeSkeleton writes just the static text of the template, taking either the "if" or "else" path
+// through any conditionals.
+func writeSkeleton(out *strings.Builder, node parse.Node, useElse bool) {
+	var branch parse.BranchNode
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			writeSkeleton(out, child, useElse)
+		}
+		return
+	case *parse.TextNode:
+		out.Write(n.Text)
+		return
+	case *parse.IfNode:
+		branch = n.BranchNode
+	case *parse.RangeNode:
+		branch = n.BranchNode
+	case *parse.WithNode:
+		branch = n.BranchNode
+	default:
+		return
+	}
+
+	if useElse && branch.ElseList != nil {
+		writeSkeleton(out, branch.ElseList, useElse)
+		return
+	}
+	writeSkeleton(out, branch.List, useElse)
+}
+
+func trustSSML(value interface{}) SSML {
+	if value == nil {
+		return ""
+	}
+	return SSML(fmt.Sprint(value))
+}
+
+// escapeSSML converts the output of an action into text that is safe to put in an SSML document. Values
+// that are already SSML are written as-is.
+func escapeSSML(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case SSML:
+		return string(v)
+	case ssml.Builder:
+		return v.Fragment()
+	default:
+		return ssml.Escape(fmt.Sprint(value))
+	}
+}
@@ -2,36 +2,58 @@ package speech
 
 import (
 	"fmt"
-	"html/template"
+	"io/ioutil"
+	"math/rand"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
 	"time"
 
+	"github.com/robsignorelli/golexa/ssml"
 	"golang.org/x/text/language"
 )
 
 // NewTemplate creates a brand new template for one of your possible responses. It includes an
 // English (US) translation by default, but you can use the `WithTranslation` option to define
 // translations for other languages. Since the translations use standard Go templates, you can
-// define custom functions that should be available in this template using `WithFunc`.
+// define custom functions that should be available in this template using `WithFunc`. Every
+// template also has functions like "number", "date", and "list" that format values based on the
+// language of the request (see the README for the full list).
+//
+// Templates are SSML-aware. Every value you interpolate (e.g. "{{.Value}}") is escaped so that a
+// slot value like "Tom & Jerry" can't break your speech, while the markup you write in the template
+// itself is left alone. If any translation can't produce valid SSML (e.g. an unclosed tag or one that
+// Alexa doesn't support), this will panic - the same way `template.Must()` does for a parse error.
 func NewTemplate(englishSpeech string, options ...TemplateOption) Template {
 	t := Template{
+		key:          fmt.Sprintf("#%d", atomic.AddUint64(&templateCount, 1)),
 		funcMap:      template.FuncMap{},
-		translations: map[language.Tag]*template.Template{},
+		translations: map[language.Tag][]*template.Template{},
+		localized:    &sync.Map{},
 	}
 
 	// Make sure that the funcMap is populated before attempting to do the translations
 	// otherwise they'll likely fail if the function hasn't been added to the template yet.
 	// This allows you to have any oder of WithFunc() and WithTranslation() that you want.
-	sort.Slice(options, func(i, j int) bool {
+	sort.SliceStable(options, func(i, j int) bool {
 		return options[i].order < options[j].order
 	})
 	for _, opt := range options {
-		opt.apply(&t)
+		if opt.order < 2 {
+			opt.apply(&t)
+		}
 	}
 
-	// Your required default translation trumps any attempts to WithTranslation it away later.
+	// Your required default translation trumps any attempts to WithTranslation it away later. We
+	// apply it before any variants, though, so that you can still add English variants.
 	WithTranslation(language.AmericanEnglish, englishSpeech).apply(&t)
+	for _, opt := range options {
+		if opt.order >= 2 {
+			opt.apply(&t)
+		}
+	}
 	return t
 }
 
@@ -40,13 +62,48 @@ func NewTemplate(englishSpeech string, options ...TemplateOption) Template {
 // you should create 5 of these so that you can decide which one to evaluate. A golexa template also
 // supports defining translations for other languages based on the incoming request's language.
 type Template struct {
+	key          string
 	funcMap      template.FuncMap
-	translations map[language.Tag]*template.Template
+	translations map[language.Tag][]*template.Template
+	selection    Selection
+	localized    *sync.Map
+}
+
+// localizedKey identifies one of the translation's variants once it's been localized for a request
+// language (see `localize()`).
+type localizedKey struct {
+	variant *template.Template
+	lang    language.Tag
 }
 
 // Eval processes the parsed template using the given contextual data.
 func (t Template) Eval(ctx TemplateContext) (string, error) {
-	localizedTemplate := t.translationFor(ctx.Language)
+	lang, variants := t.translationFor(ctx.Language)
+	return t.evalVariant(variants[t.selectVariant(ctx, len(variants))], lang, ctx)
+}
+
+// EvalVariants evaluates every variant of the best translation for the context's language, in the
+// order you defined them. This is handy when you need all of the ways Alexa might say something, such
+// as when exporting the prompts in your interaction model.
+func (t Template) EvalVariants(ctx TemplateContext) ([]string, error) {
+	lang, variants := t.translationFor(ctx.Language)
+	outputs := make([]string, len(variants))
+	for i, variant := range variants {
+		output, err := t.evalVariant(variant, lang, ctx)
+		if err != nil {
+			return nil, err
+		}
+		outputs[i] = output
+	}
+	return outputs, nil
+}
+
+// evalVariant executes a single variant of the translation for the given language.
+func (t Template) evalVariant(variant *template.Template, translationLang language.Tag, ctx TemplateContext) (string, error) {
+	localizedTemplate, err := t.localize(variant, translationLang, ctx.Language)
+	if err != nil {
+		return "", fmt.Errorf("template eval: %v", err)
+	}
 	output := strings.Builder{}
 
 	if err := localizedTemplate.Execute(&output, ctx); err != nil {
@@ -55,19 +112,66 @@ func (t Template) Eval(ctx TemplateContext) (string, error) {
 	return strings.TrimSpace(output.String()), nil
 }
 
-func (t Template) translationFor(lang language.Tag) *template.Template {
-	if localizedTemplate := t.translations[lang]; localizedTemplate != nil {
-		return localizedTemplate
+// EvalText evaluates the template just like `Eval()`, but strips out any SSML so that you can show the
+// result on a card or screen (e.g. "Tom &amp; Jerry <break/> rock" becomes "Tom & Jerry rock").
+func (t Template) EvalText(ctx TemplateContext) (string, error) {
+	output, err := t.Eval(ctx)
+	if err != nil {
+		return "", err
+	}
+	return ssml.PlainText(output), nil
+}
+
+// translationFor finds the variants of the best translation for the given language as well as the
+// language of that translation.
+func (t Template) translationFor(lang language.Tag) (language.Tag, []*template.Template) {
+	if variants := t.translations[lang]; len(variants) > 0 {
+		return lang, variants
 	}
 	// We've gone from "es-MX" to "es-241" to "es" and still no translation. We're now
 	// at "und", so fall back to the English translation.
 	if lang.IsRoot() {
-		return t.translations[language.AmericanEnglish]
+		return language.AmericanEnglish, t.translations[language.AmericanEnglish]
 	}
 	return t.translationFor(lang.Parent())
 }
 
-// WithFunc adds a named function that will be available when parsing/evaluating responses.
+// localize makes sure that formatting functions like "number" and "list" use the language of the
+// request rather than the translation we fell back to. For instance, an "es-MX" request might use
+// your "es" translation, and an "en-GB" request uses the English one. Any functions you registered
+// w/ the same names as the built-in ones are left alone. Most requests end up here (e.g. "en-GB" and
+// "es-MX"), so we hang onto the localized clone for each language rather than cloning every time.
+func (t Template) localize(localizedTemplate *template.Template, translationLang language.Tag, lang language.Tag) (*template.Template, error) {
+	if lang == language.Und || lang == translationLang {
+		return localizedTemplate, nil
+	}
+
+	key := localizedKey{variant: localizedTemplate, lang: lang}
+	if t.localized != nil {
+		if cached, ok := t.localized.Load(key); ok {
+			return cached.(*template.Template), nil
+		}
+	}
+
+	funcs := formatFuncs(lang)
+	for name := range t.funcMap {
+		delete(funcs, name)
+	}
+	clone, err := localizedTemplate.Clone()
+	if err != nil {
+		return nil, err
+	}
+	clone = clone.Funcs(funcs)
+
+	if t.localized != nil {
+		t.localized.Store(key, clone)
+	}
+	return clone, nil
+}
+
+// WithFunc adds a named function that will be available when parsing/evaluating responses. Whatever
+// your function returns is escaped like any other value, so return an `SSML` value if your function
+// generates markup of its own.
 func WithFunc(name string, function interface{}) TemplateOption {
 	return TemplateOption{
 		order: 0,
@@ -83,15 +187,77 @@ func WithTranslation(lang language.Tag, localizedSpeech string) TemplateOption {
 	return TemplateOption{
 		order: 1,
 		apply: func(t *Template) {
-			t.translations[lang] = template.Must(template.New(lang.String()).
-				Funcs(t.funcMap).
-				Parse(localizedSpeech))
+			t.translations[lang] = []*template.Template{mustParseSSML(lang, localizedSpeech, t.funcMap)}
 		},
 	}
 }
 
-// TemplateOption should not be used directly. Use WithFunc or WithTranslation to provide the
-// template option of your choice.
+// WithSampleValue catches typos in your templates (e.g. "{{.Value.Nmae}}") when you create them rather
+// than when a user finally hits that response in production. We evaluate every translation/variant
+// w/ a sample of the value you'll pass when speaking it, and panic if any of them fail:
+//
+//	speech.NewTemplate("Added {{.Value.Name}}", speech.WithSampleValue(TodoItem{}))
+//
+// Go templates only evaluate the branches they actually take, so the sample only checks the fields
+// that it reaches. A zero value skips the body of "{{if .Value.Done}}", for instance. If your template
+// uses "{{.Request}}", use `WithSampleContext` instead so you can give it a sample request, too.
+func WithSampleValue(value interface{}) TemplateOption {
+	return WithSampleContext(TemplateContext{Value: value})
+}
+
+// WithSampleContext works just like `WithSampleValue`, but lets you fill in the rest of the context
+// for templates that use "{{.Request}}", "{{.Session}}", "{{.Data}}", and so on:
+//
+//	speech.NewTemplate("Hi {{.Request.Session.User.ID}}, you added {{.Value.Name}}",
+//	    speech.WithSampleContext(speech.TemplateContext{Request: golexa.Request{}, Value: TodoItem{}}))
+//
+// The language is set to each translation's language as we check it, and we fill in the current time
+// and empty session/data maps if you leave them out.
+func WithSampleContext(ctx TemplateContext) TemplateOption {
+	if ctx.Now.IsZero() {
+		ctx.Now = time.Now()
+	}
+	if ctx.Session == nil {
+		ctx.Session = map[string]interface{}{}
+	}
+	if ctx.Data == nil {
+		ctx.Data = map[string]interface{}{}
+	}
+	return TemplateOption{
+		order: 3,
+		apply: func(t *Template) {
+			var langs []language.Tag
+			for lang := range t.translations {
+				langs = append(langs, lang)
+			}
+			sort.Slice(langs, func(i, j int) bool { return langs[i].String() < langs[j].String() })
+
+			for _, lang := range langs {
+				ctx.Language = lang
+				for _, variant := range t.translations[lang] {
+					if err := variant.Execute(ioutil.Discard, ctx); err != nil {
+						panic(fmt.Errorf("speech: %s translation doesn't work w/ the sample context: %v", lang, err))
+					}
+				}
+			}
+		},
+	}
+}
+
+func mustParseSSML(lang language.Tag, localizedSpeech string, funcs template.FuncMap) *template.Template {
+	localizedTemplate, err := parseSSML(lang, localizedSpeech, funcs)
+	if err != nil {
+		panic(fmt.Errorf("speech: invalid %s translation: %v", lang, err))
+	}
+	return localizedTemplate
+}
+
+// templateCount is used to give every template a unique key when remembering which variant we spoke
+// last (see `WithID` if you need a key that's stable across deployments).
+var templateCount uint64
+
+// TemplateOption should not be used directly. Use WithFunc, WithTranslation, WithVariants, WithSelection,
+// WithID, WithSampleValue, or WithSampleContext to provide the template option of your choice.
 type TemplateOption struct {
 	apply func(*Template)
 	order int
@@ -101,8 +267,51 @@ type TemplateOption struct {
 // when evaluating it. This gives your template access to some higher level data like
 // the current timestamp and request as well as any data you generated when handling
 // the intent that has an effect on the speech response.
+//
+// When you use `Response.SpeakTemplate()` and friends, golexa fills in the request, session,
+// device, and data for you, so your templates can personalize responses w/o every handler
+// building its own value struct (e.g. "Welcome back, {{.Data.user.Name}}").
 type TemplateContext struct {
 	Language language.Tag
 	Now      time.Time
 	Value    interface{}
+
+	// Request is the entire incoming golexa.Request (e.g. "{{.Request.Body.Intent.Name}}").
+	Request interface{}
+	// Session contains the attributes that you stored in the session on previous requests.
+	Session map[string]interface{}
+	// Device describes what the user's device is capable of (e.g. "{{if .Device.HasScreen}}").
+	Device Device
+	// Data is any per-request data that your middleware attached using `Request.WithData()`.
+	Data map[string]interface{}
+
+	// Variants remembers which variant of the template the user heard last. It's optional, but
+	// the `RoundRobin` and `NeverRepeatLast` strategies behave just like `Random` w/o it.
+	Variants VariantState
+	// Random is the random number generator used to pick variants. Leave it nil to use the shared
+	// one (see `Seed()`), or provide your own seeded instance to get deterministic output.
+	Random *rand.Rand
+}
+
+// Device describes the capabilities of the device that the user is talking to so that your templates
+// can tailor the response (e.g. "Check the screen" vs "Check the Alexa app").
+type Device struct {
+	// Interfaces are the names of all of the interfaces the device supports such as "AudioPlayer",
+	// "Display", or "Alexa.Presentation.APL".
+	Interfaces []string
+}
+
+// Supports returns true if the device supports the interface w/ the given name.
+func (d Device) Supports(interfaceName string) bool {
+	for _, name := range d.Interfaces {
+		if name == interfaceName {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScreen returns true if the device can display visual content.
+func (d Device) HasScreen() bool {
+	return d.Supports("Alexa.Presentation.APL") || d.Supports("Display")
 }
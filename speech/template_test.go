@@ -0,0 +1,148 @@
+package speech_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/robsignorelli/golexa"
+	"github.com/robsignorelli/golexa/speech"
+	"github.com/robsignorelli/golexa/ssml"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/text/language"
+)
+
+func TestTemplateSuite(t *testing.T) {
+	suite.Run(t, new(TemplateSuite))
+}
+
+type TemplateSuite struct {
+	suite.Suite
+}
+
+func (suite TemplateSuite) eval(template speech.Template, lang language.Tag, value interface{}) string {
+	output, err := template.Eval(speech.TemplateContext{Language: lang, Value: value})
+	suite.Require().NoError(err, "Should evaluate the template")
+	return output
+}
+
+func (suite TemplateSuite) TestEscaping() {
+	t := speech.NewTemplate(`I added "{{.Value}}" to your list.`)
+	suite.Equal(`I added "Tom &amp; Jerry&apos;s &lt;3" to your list.`, suite.eval(t, language.AmericanEnglish, "Tom & Jerry's <3"),
+		"Should escape interpolated values as XML")
+
+	t = speech.NewTemplate(`{{range .Value}}{{. | upper}}. {{end}}`, speech.WithFunc("upper", strings.ToUpper))
+	suite.Equal("A &amp; B. C. D &amp; E.", suite.eval(t, language.AmericanEnglish, []string{"a & b", "c", "d & e"}),
+		"Should escape the output of custom functions and ranges")
+
+	t = speech.NewTemplate(`{{$name := .Value}}{{if $name}}Hi {{$name}}{{else}}Hi stranger{{end}}`)
+	suite.Equal("Hi Al &amp; Bo", suite.eval(t, language.AmericanEnglish, "Al & Bo"), "Should escape inside of conditionals")
+	suite.Equal("Hi stranger", suite.eval(t, language.AmericanEnglish, ""), "Should not output variable declarations")
+
+	t = speech.NewTemplate(`{{.Value}}`, speech.WithTranslation(language.Spanish, `¡{{.Value}}!`))
+	suite.Equal("¡Q&amp;A!", suite.eval(t, language.Spanish, "Q&A"), "Should escape values in translations")
+	suite.Equal("", suite.eval(t, language.AmericanEnglish, nil), "Should output nothing for nil values")
+}
+
+func (suite TemplateSuite) TestTrustedSSML() {
+	t := speech.NewTemplate(`<speak>Wait for it <break time="1s"/> {{.Value}}</speak>`)
+	suite.Equal(`<speak>Wait for it <break time="1s"/> woof &amp; bark</speak>`, suite.eval(t, language.AmericanEnglish, "woof & bark"),
+		"Should leave markup in the template alone")
+
+	t = speech.NewTemplate(`The winner is {{.Value}}`)
+	suite.Equal(`The winner is <emphasis level="strong">Bob</emphasis>`,
+		suite.eval(t, language.AmericanEnglish, speech.SSML(`<emphasis level="strong">Bob</emphasis>`)),
+		"Should not escape SSML values")
+	suite.Equal(`The winner is <emphasis level="strong">A &amp; B</emphasis>`,
+		suite.eval(t, language.AmericanEnglish, ssml.New().Emphasis("strong", "A & B")),
+		"Should not escape builder values")
+
+	t = speech.NewTemplate(`Listen: {{ssml .Value}}`)
+	suite.Equal(`Listen: <audio src="https://example.com/a.mp3"/>`,
+		suite.eval(t, language.AmericanEnglish, `<audio src="https://example.com/a.mp3"/>`),
+		"Should not escape values passed through the ssml function")
+}
+
+func (suite TemplateSuite) TestInvalidTemplates() {
+	for _, text := range []string{
+		"Tom & Jerry",
+		"<speak>Unclosed {{.Value}}",
+		`<emphasis level="super">{{.Value}}</emphasis>`,
+		`<blink>{{.Value}}</blink>`,
+		`{{if .Value}}<emphasis>{{.Value}}{{else}}none{{end}}</emphasis>`,
+		"{{.Value",
+	} {
+		suite.Panics(func() { speech.NewTemplate(text) }, "Should panic on invalid template: "+text)
+		suite.Panics(func() { speech.NewTemplate("Hi", speech.WithTranslation(language.Spanish, text)) },
+			"Should panic on invalid translation: "+text)
+	}
+
+	suite.NotPanics(func() {
+		speech.NewTemplate(`{{if .Value}}<emphasis>{{.Value}}</emphasis>{{else}}<break/>{{end}}`)
+	}, "Should validate each branch of a conditional")
+}
+
+func (suite TemplateSuite) TestEvalText() {
+	t := speech.NewTemplate(`<speak>{{.Value}} <break time="1s"/> <emphasis>rocks</emphasis>!</speak>`)
+	output, err := t.EvalText(speech.TemplateContext{Value: "Tom & Jerry"})
+	suite.Require().NoError(err, "Should evaluate the template")
+	suite.Equal("Tom & Jerry rocks!", output, "Should strip the SSML out of the output")
+
+	_, err = speech.NewTemplate("{{.Value.Nope}}").EvalText(speech.TemplateContext{Value: "x"})
+	suite.Error(err, "Should fail when the template fails")
+}
+
+func (suite TemplateSuite) TestSampleValue() {
+	type item struct {
+		Name string
+		Done bool
+	}
+
+	suite.NotPanics(func() {
+		speech.NewTemplate("Added {{.Value.Name}}",
+			speech.WithTranslation(language.Spanish, "Agregué {{.Value.Name}}"),
+			speech.WithVariants(language.AmericanEnglish, "I added {{.Value.Name}}"),
+			speech.WithSampleValue(item{}))
+	}, "Should accept templates that work w/ the sample value")
+
+	suite.Panics(func() {
+		speech.NewTemplate("Added {{.Value.Nmae}}", speech.WithSampleValue(item{}))
+	}, "Should panic when the English text has a typo")
+	suite.Panics(func() {
+		speech.NewTemplate("Added {{.Value.Name}}",
+			speech.WithTranslation(language.Spanish, "Agregué {{.Value.Nmae}}"),
+			speech.WithSampleValue(item{}))
+	}, "Should panic when a translation has a typo")
+	suite.Panics(func() {
+		speech.NewTemplate("Added {{.Value.Name}}",
+			speech.WithSampleValue(item{}),
+			speech.WithVariants(language.AmericanEnglish, "I added {{.Value.Nmae}}"))
+	}, "Should check variants regardless of option order")
+
+	suite.NotPanics(func() {
+		speech.NewTemplate("{{if .Value.Done}}{{.Value.Nmae}}{{else}}Not done{{end}}", speech.WithSampleValue(item{}))
+	}, "Should only check the branches that the sample value reaches")
+	suite.NotPanics(func() {
+		speech.NewTemplate("{{.Value.Nmae}}")
+	}, "Should not check anything w/o a sample value")
+}
+
+func (suite TemplateSuite) TestSampleContext() {
+	type item struct {
+		Name string
+	}
+	text := "Hi {{.Request.Session.User.ID}}, you added {{.Value.Name}} {{.Data.list}} {{if .Device.HasScreen}}!{{end}}"
+
+	suite.NotPanics(func() {
+		speech.NewTemplate(text,
+			speech.WithTranslation(language.Spanish, "Hola {{.Request.Session.User.ID}}, agregaste {{.Value.Name}}"),
+			speech.WithSampleContext(speech.TemplateContext{Request: golexa.Request{}, Value: item{}}))
+	}, "Should accept templates that use the request when given a sample request")
+
+	suite.Panics(func() {
+		speech.NewTemplate(text, speech.WithSampleValue(item{}))
+	}, "Should panic when the template uses the request, but there's no sample request")
+	suite.Panics(func() {
+		speech.NewTemplate("Hi {{.Request.Session.User.Nope}}",
+			speech.WithSampleContext(speech.TemplateContext{Request: golexa.Request{}}))
+	}, "Should panic when the template has a typo in the request fields")
+}
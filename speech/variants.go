@@ -0,0 +1,179 @@
+package speech
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// WithVariants adds alternative versions of the response for the given language. Hearing the exact
+// same phrase every time gets stale, so each time you evaluate the template, one of the variants is
+// chosen using the template's selection strategy (random by default - see `WithSelection`). The
+// variants are added to whatever `WithTranslation` (or `NewTemplate` for English) already defined, so
+// this gives you three possible greetings:
+//
+//	speech.NewTemplate("Hello {{.Value}}",
+//	    speech.WithVariants(language.AmericanEnglish, "Hi {{.Value}}", "Hey there, {{.Value}}"))
+func WithVariants(lang language.Tag, variants ...string) TemplateOption {
+	return TemplateOption{
+		order: 2,
+		apply: func(t *Template) {
+			for _, variant := range variants {
+				t.translations[lang] = append(t.translations[lang], mustParseSSML(lang, variant, t.funcMap))
+			}
+		},
+	}
+}
+
+// WithSelection changes the strategy used to decide which variant to speak when a translation has
+// more than one (see `WithVariants`). The default is `Random`.
+func WithSelection(selection Selection) TemplateOption {
+	return TemplateOption{
+		order: 0,
+		apply: func(t *Template) {
+			t.selection = selection
+		},
+	}
+}
+
+// WithID gives the template a name that identifies it when remembering which variant was spoken last
+// (see `VariantState`). By default, each template gets a unique key based on the order you created
+// them in, which is fine as long as you create your templates the same way every time your skill
+// starts up. Use this when that's not the case or you don't want a deployment that adds templates to
+// reset the variants your users were in the middle of. Every template must have its own id.
+func WithID(id string) TemplateOption {
+	return TemplateOption{
+		order: 0,
+		apply: func(t *Template) {
+			t.key = "id:" + id
+		},
+	}
+}
+
+// Selection decides which of the variants to speak. It's given the number of variants, the index of
+// the variant that was spoken last time (-1 if we don't know), and a function that generates a random
+// number in the range [0,n). It should return the index of the variant to speak.
+type Selection func(count int, last int, random func(n int) int) int
+
+// Random picks any of the variants w/ equal probability.
+func Random(count int, _ int, random func(n int) int) int {
+	return random(count)
+}
+
+// NeverRepeatLast randomly picks any of the variants except for the one the user heard last time.
+func NeverRepeatLast(count int, last int, random func(n int) int) int {
+	if last < 0 || last >= count || count < 2 {
+		return random(count)
+	}
+	// Pick from the other (count-1) variants and skip over the last one.
+	index := random(count - 1)
+	if index >= last {
+		index++
+	}
+	return index
+}
+
+// RoundRobin speaks each of the variants in order, starting over once the user has heard them all.
+func RoundRobin(count int, last int, _ func(n int) int) int {
+	return (last + 1) % count
+}
+
+// Weighted randomly picks a variant where the odds of each are proportional to its weight, so
+// `Weighted(3, 1)` speaks the first variant 75% of the time. Any variants w/o a weight have a weight of 1.
+func Weighted(weights ...int) Selection {
+	return func(count int, _ int, random func(n int) int) int {
+		weightOf := func(i int) int {
+			if i < len(weights) {
+				if weights[i] < 0 {
+					return 0
+				}
+				return weights[i]
+			}
+			return 1
+		}
+
+		total := 0
+		for i := 0; i < count; i++ {
+			total += weightOf(i)
+		}
+		if total == 0 {
+			return random(count)
+		}
+
+		roll := random(total)
+		for i := 0; i < count; i++ {
+			if roll < weightOf(i) {
+				return i
+			}
+			roll -= weightOf(i)
+		}
+		return count - 1
+	}
+}
+
+// VariantState remembers which variant of each template was spoken last. Strategies like `RoundRobin`
+// and `NeverRepeatLast` need this to work across requests. When you use `Response.SpeakTemplate()`,
+// golexa stores this in the session attributes for you, so it's tracked per user/conversation.
+type VariantState interface {
+	// LastVariant returns the index of the variant that was spoken last for the template w/ the given key.
+	LastVariant(key string) (int, bool)
+	// RememberVariant records the index of the variant that we just spoke for the given template.
+	RememberVariant(key string, index int)
+}
+
+// Seed resets the random number generator used to pick variants when the template context doesn't
+// have its own `Random`. This lets your unit tests assert exactly which variant your handlers speak.
+func Seed(seed int64) {
+	defaultRandom.Lock()
+	defer defaultRandom.Unlock()
+	defaultRandom.source = rand.New(rand.NewSource(seed))
+}
+
+var defaultRandom = struct {
+	sync.Mutex
+	source *rand.Rand
+}{source: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+// randomFunc returns a function that generates random numbers in [0,n) using either the context's
+// own generator or our shared (goroutine-safe) one.
+func randomFunc(ctx TemplateContext) func(n int) int {
+	if ctx.Random != nil {
+		return ctx.Random.Intn
+	}
+	return func(n int) int {
+		defaultRandom.Lock()
+		defer defaultRandom.Unlock()
+		return defaultRandom.source.Intn(n)
+	}
+}
+
+// selectVariant picks which of the count variants to speak, remembering the choice in the context's
+// variant state if there is one.
+func (t Template) selectVariant(ctx TemplateContext, count int) int {
+	if count < 2 {
+		return 0
+	}
+
+	last := -1
+	if ctx.Variants != nil {
+		if index, ok := ctx.Variants.LastVariant(t.key); ok && index >= 0 && index < count {
+			last = index
+		}
+	}
+
+	selection := t.selection
+	if selection == nil {
+		selection = Random
+	}
+	index := selection(count, last, randomFunc(ctx))
+	if index < 0 || index >= count {
+		index = 0
+	}
+
+	if ctx.Variants != nil {
+		ctx.Variants.RememberVariant(t.key, index)
+	}
+	return index
+}
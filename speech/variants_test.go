@@ -0,0 +1,130 @@
+package speech_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/robsignorelli/golexa/speech"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/text/language"
+)
+
+func TestVariantsSuite(t *testing.T) {
+	suite.Run(t, new(VariantsSuite))
+}
+
+type VariantsSuite struct {
+	suite.Suite
+}
+
+// memoryState is a bare-bones VariantState that just keeps everything in a map.
+type memoryState map[string]int
+
+func (m memoryState) LastVariant(key string) (int, bool) {
+	index, ok := m[key]
+	return index, ok
+}
+
+func (m memoryState) RememberVariant(key string, index int) {
+	m[key] = index
+}
+
+func (suite VariantsSuite) evalAll(t speech.Template, ctx speech.TemplateContext, times int) []string {
+	var results []string
+	for i := 0; i < times; i++ {
+		output, err := t.Eval(ctx)
+		suite.Require().NoError(err, "Should evaluate the template")
+		results = append(results, output)
+	}
+	return results
+}
+
+func (suite VariantsSuite) TestVariants() {
+	t := speech.NewTemplate("Hello",
+		speech.WithVariants(language.AmericanEnglish, "Hi", "Hey"),
+		speech.WithTranslation(language.Spanish, "Hola"),
+		speech.WithVariants(language.Spanish, "Buenas"),
+		speech.WithSelection(speech.RoundRobin))
+
+	ctx := speech.TemplateContext{Language: language.AmericanEnglish, Variants: memoryState{}}
+	suite.Equal([]string{"Hello", "Hi", "Hey", "Hello"}, suite.evalAll(t, ctx, 4),
+		"Should add English variants after the default speech")
+
+	ctx = speech.TemplateContext{Language: language.MustParse("es-MX"), Variants: memoryState{}}
+	suite.Equal([]string{"Hola", "Buenas", "Hola"}, suite.evalAll(t, ctx, 3),
+		"Should add variants to translations")
+
+	ctx = speech.TemplateContext{Language: language.AmericanEnglish}
+	suite.Equal([]string{"Hello", "Hello"}, suite.evalAll(t, ctx, 2),
+		"Round robin should start over w/o any state")
+
+	single := speech.NewTemplate("Only", speech.WithSelection(speech.RoundRobin))
+	state := memoryState{}
+	suite.Equal([]string{"Only"}, suite.evalAll(single, speech.TemplateContext{Variants: state}, 1),
+		"Should speak templates w/o variants")
+	suite.Empty(state, "Should not track state for templates w/o variants")
+}
+
+func (suite VariantsSuite) TestSeed() {
+	t := speech.NewTemplate("A", speech.WithVariants(language.AmericanEnglish, "B", "C", "D", "E"))
+
+	speech.Seed(42)
+	first := suite.evalAll(t, speech.TemplateContext{}, 10)
+	speech.Seed(42)
+	suite.Equal(first, suite.evalAll(t, speech.TemplateContext{}, 10), "Should be deterministic w/ the same seed")
+
+	ctx := speech.TemplateContext{Random: rand.New(rand.NewSource(7))}
+	expected := suite.evalAll(t, ctx, 10)
+	ctx.Random = rand.New(rand.NewSource(7))
+	suite.Equal(expected, suite.evalAll(t, ctx, 10), "Should use the context's random generator")
+}
+
+func (suite VariantsSuite) TestNeverRepeatLast() {
+	t := speech.NewTemplate("A",
+		speech.WithVariants(language.AmericanEnglish, "B", "C"),
+		speech.WithSelection(speech.NeverRepeatLast))
+
+	ctx := speech.TemplateContext{Variants: memoryState{}, Random: rand.New(rand.NewSource(1))}
+	results := suite.evalAll(t, ctx, 50)
+	for i := 1; i < len(results); i++ {
+		suite.NotEqual(results[i-1], results[i], "Should never speak the same variant twice in a row")
+	}
+}
+
+func (suite VariantsSuite) TestKeys() {
+	first := speech.NewTemplate("Hello", speech.WithVariants(language.AmericanEnglish, "Hi"), speech.WithSelection(speech.RoundRobin))
+	second := speech.NewTemplate("Hello", speech.WithVariants(language.AmericanEnglish, "Hey"), speech.WithSelection(speech.RoundRobin))
+
+	ctx := speech.TemplateContext{Variants: memoryState{}}
+	suite.Equal([]string{"Hello", "Hi"}, suite.evalAll(first, ctx, 2), "Should round robin the first template")
+	suite.Equal([]string{"Hello", "Hey"}, suite.evalAll(second, ctx, 2),
+		"Should not share state between templates w/ the same English text")
+	suite.Len(ctx.Variants, 2, "Should track each template separately")
+
+	named := func() speech.Template {
+		return speech.NewTemplate("Bye", speech.WithVariants(language.AmericanEnglish, "Later"),
+			speech.WithSelection(speech.RoundRobin), speech.WithID("goodbye"))
+	}
+	ctx = speech.TemplateContext{Variants: memoryState{}}
+	suite.Equal([]string{"Bye"}, suite.evalAll(named(), ctx, 1), "Should round robin named templates")
+	suite.Equal([]string{"Later"}, suite.evalAll(named(), ctx, 1),
+		"Should share state between templates w/ the same id")
+}
+
+func (suite VariantsSuite) TestSelections() {
+	fixed := func(value int) func(int) int {
+		return func(int) int { return value }
+	}
+
+	suite.Equal(2, speech.Random(3, 0, fixed(2)), "Random should use the random number")
+	suite.Equal(0, speech.RoundRobin(3, -1, fixed(2)), "RoundRobin should start at the first variant")
+	suite.Equal(0, speech.RoundRobin(3, 2, fixed(2)), "RoundRobin should wrap around")
+	suite.Equal(2, speech.NeverRepeatLast(3, 1, fixed(1)), "NeverRepeatLast should skip the last variant")
+	suite.Equal(0, speech.NeverRepeatLast(3, 1, fixed(0)), "NeverRepeatLast should pick variants before the last")
+
+	weighted := speech.Weighted(3, 0, 1)
+	suite.Equal(0, weighted(3, -1, fixed(0)), "Weighted should pick based on the weight ranges")
+	suite.Equal(0, weighted(3, -1, fixed(2)), "Weighted should pick based on the weight ranges")
+	suite.Equal(2, weighted(3, -1, fixed(3)), "Weighted should skip variants w/ no weight")
+	suite.Equal(3, speech.Weighted(1)(4, -1, fixed(3)), "Weighted should default missing weights to 1")
+}
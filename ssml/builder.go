@@ -0,0 +1,255 @@
+// Package ssml helps you build the Speech Synthesis Markup Language that Alexa uses to control how
+// your responses sound (pauses, emphasis, whispering, etc). Everything you pass in as text is
+// properly escaped, so values like "Tom & Jerry" never break your response.
+//
+// See: https://developer.amazon.com/en-US/docs/alexa/custom-skills/speech-synthesis-markup-language-ssml-reference.html
+package ssml
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Attrs are the attributes for an SSML tag (e.g. {"level": "strong"}).
+type Attrs map[string]string
+
+// Prosody controls the rate, pitch, and volume of the speech. Leave any of them blank to use
+// Alexa's default.
+type Prosody struct {
+	// Rate is "x-slow", "slow", "medium", "fast", "x-fast", or a percentage like "150%".
+	Rate string
+	// Pitch is "x-low", "low", "medium", "high", "x-high", or a relative percentage like "+10%".
+	Pitch string
+	// Volume is "silent", "x-soft", "soft", "medium", "loud", "x-loud", or a relative change like "+6dB".
+	Volume string
+}
+
+// New creates an empty builder that you can append text and SSML tags to.
+func New() Builder {
+	return Builder{}
+}
+
+// Builder is a fluent helper for generating SSML. Just like golexa responses, each method returns a
+// copy w/ the new content appended, so you can safely reuse a partially built instance.
+//
+//	speech := ssml.New().
+//	    Text("The winner is").
+//	    Break("strong").
+//	    Emphasis("strong", winnerName).
+//	    String()
+type Builder struct {
+	parts []string
+}
+
+// String generates the complete SSML document, wrapped in a <speak> tag.
+func (b Builder) String() string {
+	return "<speak>" + b.Fragment() + "</speak>"
+}
+
+// Fragment generates the SSML w/o the wrapping <speak> tag so that you can nest it using `Element()`
+// or embed it in a larger document. The parts are concatenated as-is, so include any spaces you want
+// Alexa to pause on in your text (e.g. `Text("Stop").Text("!")` is just "Stop!").
+func (b Builder) Fragment() string {
+	return strings.Join(b.parts, "")
+}
+
+// Build generates the complete SSML document and validates it, so you find out about typos in your
+// attribute values before Alexa rejects your response.
+func (b Builder) Build() (string, error) {
+	doc := b.String()
+	return doc, Validate(doc)
+}
+
+// Text appends plain text, escaping any characters that have special meaning in SSML.
+func (b Builder) Text(text string) Builder {
+	return b.append(Escape(text))
+}
+
+// Trusted appends SSML that you have already built/escaped yourself exactly as-is.
+func (b Builder) Trusted(ssml string) Builder {
+	return b.append(ssml)
+}
+
+// Break inserts a pause whose length is based on the given strength: "none", "x-weak", "weak",
+// "medium", "strong", or "x-strong".
+func (b Builder) Break(strength string) Builder {
+	return b.append(tag("break", Attrs{"strength": strength}, ""))
+}
+
+// Pause inserts a pause of the given duration (up to 10 seconds).
+func (b Builder) Pause(duration time.Duration) Builder {
+	return b.append(tag("break", Attrs{"time": fmt.Sprintf("%dms", int64(duration/time.Millisecond))}, ""))
+}
+
+// Emphasis speaks the text w/ the given level of emphasis: "strong", "moderate", or "reduced".
+func (b Builder) Emphasis(level string, text string) Builder {
+	return b.append(tag("emphasis", Attrs{"level": level}, Escape(text)))
+}
+
+// Prosody speaks the text w/ a modified rate, pitch, and/or volume.
+func (b Builder) Prosody(prosody Prosody, text string) Builder {
+	attrs := Attrs{}
+	if prosody.Rate != "" {
+		attrs["rate"] = prosody.Rate
+	}
+	if prosody.Pitch != "" {
+		attrs["pitch"] = prosody.Pitch
+	}
+	if prosody.Volume != "" {
+		attrs["volume"] = prosody.Volume
+	}
+	return b.append(tag("prosody", attrs, Escape(text)))
+}
+
+// SayAs tells Alexa how to interpret the text, such as "characters", "cardinal", "ordinal", "digits",
+// "date", "telephone", or "interjection".
+func (b Builder) SayAs(interpretAs string, text string) Builder {
+	return b.append(tag("say-as", Attrs{"interpret-as": interpretAs}, Escape(text)))
+}
+
+// SayAsDate speaks the text as a date using the given format such as "mdy" or "ymd".
+func (b Builder) SayAsDate(format string, text string) Builder {
+	return b.append(tag("say-as", Attrs{"interpret-as": "date", "format": format}, Escape(text)))
+}
+
+// Audio plays the MP3 at the given HTTPS URL.
+func (b Builder) Audio(src string) Builder {
+	return b.append(tag("audio", Attrs{"src": src}, ""))
+}
+
+// Lang speaks the text using the pronunciation for the given locale (e.g. "fr-FR").
+func (b Builder) Lang(locale string, text string) Builder {
+	return b.append(tag("lang", Attrs{"xml:lang": locale}, Escape(text)))
+}
+
+// Voice speaks the text using one of the Amazon Polly voices (e.g. "Matthew").
+func (b Builder) Voice(name string, text string) Builder {
+	return b.append(tag("voice", Attrs{"name": name}, Escape(text)))
+}
+
+// Effect applies a special effect to the speech. Currently, Alexa only supports "whispered".
+func (b Builder) Effect(name string, text string) Builder {
+	return b.append(tag("amazon:effect", Attrs{"name": name}, Escape(text)))
+}
+
+// Domain speaks the text in a style suited to the given domain such as "conversational",
+// "news", "music", "long-form", or "fun".
+func (b Builder) Domain(name string, text string) Builder {
+	return b.append(tag("amazon:domain", Attrs{"name": name}, Escape(text)))
+}
+
+// Phoneme tells Alexa exactly how to pronounce the text using the given phonetic alphabet
+// ("ipa" or "x-sampa").
+func (b Builder) Phoneme(alphabet string, phonemes string, text string) Builder {
+	return b.append(tag("phoneme", Attrs{"alphabet": alphabet, "ph": phonemes}, Escape(text)))
+}
+
+// Sub has Alexa say the alias instead of the text (e.g. "aluminum" for "Al").
+func (b Builder) Sub(alias string, text string) Builder {
+	return b.append(tag("sub", Attrs{"alias": alias}, Escape(text)))
+}
+
+// Element wraps the content of another builder in any SSML tag. Use this when you need to nest tags,
+// such as a say-as inside of a prosody.
+func (b Builder) Element(name string, attrs Attrs, content Builder) Builder {
+	return b.append(tag(name, attrs, content.Fragment()))
+}
+
+func (b Builder) append(part string) Builder {
+	parts := make([]string, len(b.parts), len(b.parts)+1)
+	copy(parts, b.parts)
+	b.parts = append(parts, part)
+	return b
+}
+
+// tag generates the markup for a single tag w/ its attributes in a consistent order. The content must
+// already be escaped. Tags w/o any content are self-closing.
+func tag(name string, attrs Attrs, content string) string {
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	markup := strings.Builder{}
+	markup.WriteString("<" + name)
+	for _, key := range keys {
+		markup.WriteString(" " + key + `="` + Escape(attrs[key]) + `"`)
+	}
+	if content == "" {
+		markup.WriteString("/>")
+		return markup.String()
+	}
+	markup.WriteString(">" + content + "</" + name + ">")
+	return markup.String()
+}
+
+var escaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&quot;",
+	"'", "&apos;",
+)
+
+// Escape converts characters that have special meaning in SSML (e.g. "&" and "<") into their
+// XML entities so that user-provided text can't break your speech.
+func Escape(text string) string {
+	return escaper.Replace(text)
+}
+
+// Wrap ensures that the text or SSML is a complete, valid SSML document, wrapping it in a <speak>
+// tag if necessary. Stray characters that aren't valid SSML (e.g. a slot value like "Tom & Jerry")
+// are escaped so that Alexa doesn't reject the whole response. If it's still broken after that (e.g.
+// an unclosed tag or a bad attribute value), we can't safely guess what you meant, so you get a
+// document w/ the tags stripped out that just speaks the text along w/ the validation error.
+func Wrap(textOrSSML string) (string, error) {
+	doc := textOrSSML
+	if !strings.HasPrefix(doc, "<speak") {
+		doc = "<speak>" + doc + "</speak>"
+	}
+	err := Validate(doc)
+	if err == nil {
+		return doc, nil
+	}
+	if repaired := EscapeStray(doc); Validate(repaired) == nil {
+		return repaired, nil
+	}
+	return "<speak>" + Escape(PlainText(doc)) + "</speak>", err
+}
+
+// EscapeStray only escapes the "&" and "<" characters that can't be part of your markup, leaving your
+// tags and entities alone. That way "Hi <emphasis>there</emphasis> & bye" becomes valid SSML w/o
+// Alexa reading the emphasis tags aloud. A "<" only counts as a tag when it's followed by the name of
+// an element Alexa supports, so "x<y" is treated as plain text.
+func EscapeStray(text string) string {
+	output := strings.Builder{}
+	for i := 0; i < len(text); i++ {
+		switch {
+		case text[i] == '&' && !entityPattern.MatchString(text[i:]):
+			output.WriteString("&amp;")
+		case text[i] == '<' && !tagPattern.MatchString(text[i:]):
+			output.WriteString("&lt;")
+		default:
+			output.WriteByte(text[i])
+		}
+	}
+	return output.String()
+}
+
+// entityPattern matches the start of one of the XML entities (e.g. "&amp;" or "&#8217;").
+var entityPattern = regexp.MustCompile(`^&(amp|lt|gt|quot|apos|#[0-9]+|#x[0-9a-fA-F]+);`)
+
+// tagPattern matches the start of an opening or closing tag for a supported element (e.g. "<break" or
+// "</emphasis").
+var tagPattern = func() *regexp.Regexp {
+	names := make([]string, 0, len(elementRules))
+	for name := range elementRules {
+		names = append(names, regexp.QuoteMeta(name))
+	}
+	sort.Strings(names)
+	return regexp.MustCompile(`^</?(` + strings.Join(names, "|") + `)[\s/>]`)
+}()
@@ -0,0 +1,76 @@
+package ssml_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robsignorelli/golexa/ssml"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestBuilderSuite(t *testing.T) {
+	suite.Run(t, new(BuilderSuite))
+}
+
+type BuilderSuite struct {
+	suite.Suite
+}
+
+func (suite BuilderSuite) TestEscape() {
+	suite.Equal("Tom &amp; Jerry &lt;3 &quot;quoted&quot; &apos;single&apos; &gt;",
+		ssml.Escape(`Tom & Jerry <3 "quoted" 'single' >`),
+		"Should escape all XML special characters")
+}
+
+func (suite BuilderSuite) TestEscapeStray() {
+	suite.Equal("Hi <emphasis>there</emphasis> &amp; bye", ssml.EscapeStray("Hi <emphasis>there</emphasis> & bye"),
+		"Should escape a stray ampersand w/o touching the tags")
+	suite.Equal(`Tom &amp; Jerry &lt;3 <break time="1s"/> &lt; 5`, ssml.EscapeStray(`Tom & Jerry <3 <break time="1s"/> < 5`),
+		"Should escape stray less-than signs")
+	suite.Equal("AT&amp;T &#8217; &#x2019; &amp;nbsp;", ssml.EscapeStray("AT&amp;T &#8217; &#x2019; &nbsp;"),
+		"Should leave XML entities alone, but escape ones XML doesn't know about")
+	suite.Equal("x&lt;y &lt;b>bold&lt;/b> &lt;breakfast", ssml.EscapeStray("x<y <b>bold</b> <breakfast"),
+		"Should escape less-than signs that aren't followed by a supported element name")
+	suite.Equal(`<amazon:effect name="whispered">hi</amazon:effect><p>`, ssml.EscapeStray(`<amazon:effect name="whispered">hi</amazon:effect><p>`),
+		"Should leave supported tags alone")
+	suite.Equal("Plain text", ssml.EscapeStray("Plain text"), "Should leave plain text alone")
+}
+
+func (suite BuilderSuite) TestBuilder() {
+	suite.Equal("<speak></speak>", ssml.New().String(), "Should wrap empty builders in speak tags")
+
+	base := ssml.New().Text("Tom & Jerry")
+	doc, err := base.
+		Break("strong").
+		Pause(1500*time.Millisecond).
+		Emphasis("strong", "really").
+		Prosody(ssml.Prosody{Rate: "slow", Volume: "+6dB"}, "slowly").
+		SayAs("ordinal", "3").
+		SayAsDate("mdy", "10/18/2026").
+		Audio("https://example.com/ding.mp3").
+		Lang("fr-FR", "bonjour").
+		Voice("Matthew", "hi").
+		Effect("whispered", "secret").
+		Domain("news", "headline").
+		Phoneme("ipa", "pɪˈkɑːn", "pecan").
+		Sub("aluminum", "Al").
+		Element("prosody", ssml.Attrs{"pitch": "+10%"}, ssml.New().SayAs("characters", "<a>")).
+		Trusted("<break/>").
+		Build()
+
+	suite.NoError(err, "Should generate valid SSML")
+	suite.Equal(`<speak>Tom &amp; Jerry<break strength="strong"/><break time="1500ms"/><emphasis level="strong">really</emphasis>`+
+		`<prosody rate="slow" volume="+6dB">slowly</prosody><say-as interpret-as="ordinal">3</say-as>`+
+		`<say-as format="mdy" interpret-as="date">10/18/2026</say-as><audio src="https://example.com/ding.mp3"/>`+
+		`<lang xml:lang="fr-FR">bonjour</lang><voice name="Matthew">hi</voice><amazon:effect name="whispered">secret</amazon:effect>`+
+		`<amazon:domain name="news">headline</amazon:domain><phoneme alphabet="ipa" ph="pɪˈkɑːn">pecan</phoneme>`+
+		`<sub alias="aluminum">Al</sub><prosody pitch="+10%"><say-as interpret-as="characters">&lt;a&gt;</say-as></prosody><break/></speak>`,
+		doc, "Should generate the correct markup")
+
+	suite.Equal("<speak>Tom &amp; Jerry</speak>", base.String(), "Should not modify the original builder")
+	suite.Equal("<speak>Stop!</speak>", ssml.New().Text("Stop").Text("!").String(),
+		"Should not add spaces between the parts")
+
+	_, err = ssml.New().Emphasis("super", "really").Build()
+	suite.Error(err, "Should fail to build invalid SSML")
+}
@@ -0,0 +1,51 @@
+package ssml
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// PlainText strips all of the tags out of the SSML, leaving just the text that a user would read on
+// a card or screen. Entities like "&amp;" are converted back into the characters they represent, and
+// any whitespace is collapsed, so "<speak>Tom &amp; Jerry <break time="1s"/> rock</speak>" becomes
+// "Tom & Jerry rock". Plain text that isn't wrapped in a <speak> tag works, too.
+func PlainText(doc string) string {
+	decoder := xml.NewDecoder(strings.NewReader("<root>" + doc + "</root>"))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	text := strings.Builder{}
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Whatever this is, it's not something we can parse, so just give back what we got.
+			return strings.Join(strings.Fields(doc), " ")
+		}
+
+		switch element := token.(type) {
+		case xml.CharData:
+			text.Write(element)
+		case xml.StartElement:
+			// Make sure that tags like <break/> don't smash the words on either side of them together.
+			text.WriteString(" ")
+		case xml.EndElement:
+			text.WriteString(" ")
+		}
+	}
+	return normalizeSpaces(text.String())
+}
+
+// normalizeSpaces collapses whitespace and removes any stray spaces that we added before punctuation
+// when we stripped out tags, so "<emphasis>Hi</emphasis>!" is "Hi!" rather than "Hi !".
+func normalizeSpaces(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	for _, punctuation := range []string{".", ",", "!", "?", ";", ":"} {
+		text = strings.Replace(text, " "+punctuation, punctuation, -1)
+	}
+	return text
+}
@@ -0,0 +1,26 @@
+package ssml_test
+
+import (
+	"testing"
+
+	"github.com/robsignorelli/golexa/ssml"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestTextSuite(t *testing.T) {
+	suite.Run(t, new(TextSuite))
+}
+
+type TextSuite struct {
+	suite.Suite
+}
+
+func (suite TextSuite) TestPlainText() {
+	suite.Equal("Tom & Jerry rock!", ssml.PlainText(`<speak>Tom &amp; Jerry <break time="1s"/><emphasis level="strong">rock</emphasis>!</speak>`),
+		"Should strip tags and unescape entities")
+	suite.Equal("Hello world", ssml.PlainText("  Hello\n  world "), "Should collapse whitespace in plain text")
+	suite.Equal("Chemistry: Al.", ssml.PlainText(`Chemistry: <sub alias="aluminum">Al</sub>.`), "Should keep the written text")
+	suite.Equal("Tom & Jerry", ssml.PlainText("Tom & Jerry"), "Should tolerate unescaped text")
+	suite.Equal("Broken", ssml.PlainText("<speak>Broken"), "Should do its best w/ malformed SSML")
+	suite.Equal("", ssml.PlainText(`<audio src="https://example.com/a.mp3"/>`), "Should strip out audio")
+}
@@ -0,0 +1,225 @@
+package ssml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Validate makes sure that the SSML is well-formed and only uses the tags/attributes that Alexa
+// supports. Alexa rejects the entire response when your speech is invalid, so it's much better to
+// find out before sending it. Text that isn't wrapped in a <speak> tag is validated as though it were.
+func Validate(doc string) error {
+	if !strings.HasPrefix(strings.TrimSpace(doc), "<speak") {
+		doc = "<speak>" + doc + "</speak>"
+	}
+
+	decoder := xml.NewDecoder(strings.NewReader(doc))
+	depth, roots := 0, 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("ssml: %v", err)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			name := qualifiedName(element.Name)
+			if depth == 0 && name != "speak" {
+				return fmt.Errorf("ssml: root element must be <speak>, not <%s>", name)
+			}
+			if depth == 0 {
+				roots++
+			}
+			if roots > 1 {
+				return fmt.Errorf("ssml: only one <speak> element is allowed")
+			}
+			if depth > 0 && name == "speak" {
+				return fmt.Errorf("ssml: <speak> can not be nested")
+			}
+			if err := validateElement(name, element.Attr); err != nil {
+				return err
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			if depth == 0 && strings.TrimSpace(string(element)) != "" {
+				return fmt.Errorf("ssml: text must be inside of the <speak> element")
+			}
+		}
+	}
+}
+
+// attributeRule checks a single attribute value, returning false if it is not acceptable.
+type attributeRule func(value string) bool
+
+func oneOf(values ...string) attributeRule {
+	return func(value string) bool {
+		for _, v := range values {
+			if value == v {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func matches(pattern string) attributeRule {
+	re := regexp.MustCompile(pattern)
+	return re.MatchString
+}
+
+func either(rules ...attributeRule) attributeRule {
+	return func(value string) bool {
+		for _, rule := range rules {
+			if rule(value) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func notBlank(value string) bool {
+	return strings.TrimSpace(value) != ""
+}
+
+// breakTime makes sure that break times are valid and no longer than Alexa's max of 10 seconds.
+func breakTime(value string) bool {
+	parts := regexp.MustCompile(`^(\d+(?:\.\d+)?)(s|ms)$`).FindStringSubmatch(value)
+	if parts == nil {
+		return false
+	}
+	amount, _ := strconv.ParseFloat(parts[1], 64)
+	if parts[2] == "s" {
+		amount *= 1000
+	}
+	return amount <= 10000
+}
+
+// elementRule describes the attributes an element supports and which of them are required.
+type elementRule struct {
+	attributes map[string]attributeRule
+	required   []string
+}
+
+// supportedLocales are the languages you can switch to using the <lang> tag.
+var supportedLocales = oneOf("en-US", "en-GB", "en-IN", "en-AU", "en-CA", "de-DE", "es-ES", "es-MX", "es-US",
+	"fr-FR", "fr-CA", "hi-IN", "it-IT", "ja-JP", "pt-BR")
+
+// elementRules are all of the tags that Alexa supports.
+//
+// See: https://developer.amazon.com/en-US/docs/alexa/custom-skills/speech-synthesis-markup-language-ssml-reference.html
+var elementRules = map[string]elementRule{
+	"speak": {},
+	"p":     {},
+	"s":     {},
+	"break": {attributes: map[string]attributeRule{
+		"strength": oneOf("none", "x-weak", "weak", "medium", "strong", "x-strong"),
+		"time":     breakTime,
+	}},
+	"emphasis": {attributes: map[string]attributeRule{
+		"level": oneOf("strong", "moderate", "reduced"),
+	}},
+	"prosody": {attributes: map[string]attributeRule{
+		"rate":   either(oneOf("x-slow", "slow", "medium", "fast", "x-fast"), matches(`^\d+%$`)),
+		"pitch":  either(oneOf("x-low", "low", "medium", "high", "x-high"), matches(`^[+-]\d+(\.\d+)?%$`)),
+		"volume": either(oneOf("silent", "x-soft", "soft", "medium", "loud", "x-loud"), matches(`^[+-]\d+(\.\d+)?dB$`)),
+	}},
+	"say-as": {
+		attributes: map[string]attributeRule{
+			"interpret-as": oneOf("characters", "spell-out", "cardinal", "number", "ordinal", "digits", "fraction",
+				"unit", "date", "time", "telephone", "address", "interjection", "expletive"),
+			"format": oneOf("mdy", "dmy", "ymd", "md", "dm", "ym", "my", "d", "m", "y"),
+		},
+		required: []string{"interpret-as"},
+	},
+	"audio": {
+		attributes: map[string]attributeRule{"src": matches(`^https://`)},
+		required:   []string{"src"},
+	},
+	"lang": {
+		attributes: map[string]attributeRule{"xml:lang": supportedLocales},
+		required:   []string{"xml:lang"},
+	},
+	"voice": {
+		attributes: map[string]attributeRule{"name": notBlank},
+		required:   []string{"name"},
+	},
+	"w": {
+		attributes: map[string]attributeRule{"role": oneOf("amazon:VB", "amazon:VBD", "amazon:NN", "amazon:SENSE_1")},
+		required:   []string{"role"},
+	},
+	"amazon:effect": {
+		attributes: map[string]attributeRule{"name": oneOf("whispered")},
+		required:   []string{"name"},
+	},
+	"amazon:domain": {
+		attributes: map[string]attributeRule{"name": oneOf("conversational", "long-form", "music", "news", "fun")},
+		required:   []string{"name"},
+	},
+	"amazon:emotion": {
+		attributes: map[string]attributeRule{
+			"name":      oneOf("excited", "disappointed"),
+			"intensity": oneOf("low", "medium", "high"),
+		},
+		required: []string{"name", "intensity"},
+	},
+	"phoneme": {
+		attributes: map[string]attributeRule{
+			"alphabet": oneOf("ipa", "x-sampa"),
+			"ph":       notBlank,
+		},
+		required: []string{"alphabet", "ph"},
+	},
+	"sub": {
+		attributes: map[string]attributeRule{"alias": notBlank},
+		required:   []string{"alias"},
+	},
+}
+
+func validateElement(name string, attrs []xml.Attr) error {
+	rule, ok := elementRules[name]
+	if !ok {
+		return fmt.Errorf("ssml: unsupported tag <%s>", name)
+	}
+
+	present := map[string]bool{}
+	for _, attr := range attrs {
+		attrName := qualifiedName(attr.Name)
+		check, ok := rule.attributes[attrName]
+		if !ok {
+			return fmt.Errorf("ssml: <%s> does not support the '%s' attribute", name, attrName)
+		}
+		if !check(attr.Value) {
+			return fmt.Errorf("ssml: <%s> has an invalid %s: '%s'", name, attrName, attr.Value)
+		}
+		present[attrName] = true
+	}
+	for _, attrName := range rule.required {
+		if !present[attrName] {
+			return fmt.Errorf("ssml: <%s> is missing the '%s' attribute", name, attrName)
+		}
+	}
+	return nil
+}
+
+// qualifiedName turns the decoder's namespaced names back into the prefixed form we expect (e.g. "amazon:effect").
+// Since SSML documents don't declare the "amazon" namespace, the decoder leaves the prefix in the Space field.
+func qualifiedName(name xml.Name) string {
+	switch name.Space {
+	case "":
+		return name.Local
+	case "http://www.w3.org/XML/1998/namespace":
+		return "xml:" + name.Local
+	default:
+		return name.Space + ":" + name.Local
+	}
+}
@@ -0,0 +1,55 @@
+package ssml_test
+
+import (
+	"testing"
+
+	"github.com/robsignorelli/golexa/ssml"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestValidateSuite(t *testing.T) {
+	suite.Run(t, new(ValidateSuite))
+}
+
+type ValidateSuite struct {
+	suite.Suite
+}
+
+func (suite ValidateSuite) TestValid() {
+	for _, doc := range []string{
+		"Hello world",
+		"<speak>Hello world</speak>",
+		`Hello <break time="3s"/> world`,
+		`<speak><p><s>One.</s><s>Two.</s></p></speak>`,
+		`<speak><prosody rate="150%" pitch="-5%" volume="x-loud">Hi</prosody></speak>`,
+		`<speak><amazon:emotion name="excited" intensity="high">Yay</amazon:emotion></speak>`,
+		`<speak><lang xml:lang="de-DE">Hallo</lang></speak>`,
+		`<speak><w role="amazon:VBD">read</w> Tom &amp; Jerry</speak>`,
+	} {
+		suite.NoError(ssml.Validate(doc), "Should accept valid SSML: "+doc)
+	}
+}
+
+func (suite ValidateSuite) TestInvalid() {
+	for _, doc := range []string{
+		"Tom & Jerry",
+		"<speak>Unclosed",
+		"<speak>One</speak><speak>Two</speak>",
+		"<speak><speak>Nested</speak></speak>",
+		"<div>Not SSML</div>",
+		"<speak><blink>Hi</blink></speak>",
+		`<speak><emphasis level="super">Hi</emphasis></speak>`,
+		`<speak><emphasis size="big">Hi</emphasis></speak>`,
+		`<speak><break time="11s"/></speak>`,
+		`<speak><break time="soon"/></speak>`,
+		`<speak><audio src="http://example.com/insecure.mp3"/></speak>`,
+		`<speak><say-as>3</say-as></speak>`,
+		`<speak><lang xml:lang="xx-XX">Hi</lang></speak>`,
+		`<speak><amazon:effect name="shouted">Hi</amazon:effect></speak>`,
+		`<speak><amazon:domain name="sports">Hi</amazon:domain></speak>`,
+		`<speak><phoneme alphabet="klingon" ph="x">Hi</phoneme></speak>`,
+		`<speak><sub>Al</sub></speak>`,
+	} {
+		suite.Error(ssml.Validate(doc), "Should reject invalid SSML: "+doc)
+	}
+}
@@ -7,11 +7,13 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/text/language"
 )
 
 // Start turns on the appropriate listener to handle incoming requests for the given skill. It will
@@ -21,8 +23,19 @@ import (
 // receive from the Alexa API to your Lambda function. You can use a different port by setting
 // the GOLEXA_HTTP_PORT environment variable.
 //
+// When the GOLEXA_MODEL_EXPORT environment variable is set, we don't listen for anything. Instead, we write
+// your skill's interaction model JSON to that directory and exit. This is how the "golexa model export"
+// command pulls the model out of your program.
+//
 // You should only call this once per process!
 func Start(skill Skill) {
+	if dir := os.Getenv("GOLEXA_MODEL_EXPORT"); dir != "" {
+		if err := exportModel(skill, dir, os.Getenv("GOLEXA_MODEL_LOCALES")); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	logrus.SetFormatter(&logrus.JSONFormatter{})
 	logrus.SetOutput(os.Stderr)
 	logrus.SetLevel(logrus.DebugLevel)
@@ -37,6 +50,34 @@ func Start(skill Skill) {
 	}
 }
 
+// exportModel writes one interaction model file per locale (e.g. "en-US.json") to the given directory. The
+// locales are comma separated and default to "en-US".
+func exportModel(skill Skill, dir string, locales string) error {
+	if locales == "" {
+		locales = "en-US"
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("golexa: unable to export model: %v", err)
+	}
+
+	m := skill.Model()
+	for _, locale := range strings.Split(locales, ",") {
+		locale = strings.TrimSpace(locale)
+		lang, err := language.Parse(locale)
+		if err != nil {
+			return fmt.Errorf("golexa: unable to export model: invalid locale '%s': %v", locale, err)
+		}
+		data, err := m.Export(lang)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, locale+".json"), data, 0644); err != nil {
+			return fmt.Errorf("golexa: unable to export model: %v", err)
+		}
+	}
+	return nil
+}
+
 // runningInLambda determines if we're running in a live AWS Lambda environment or if we appear
 // to be running locally (thus should fall back to an HTTP listener).
 func runningInLambda() bool {